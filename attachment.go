@@ -0,0 +1,42 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	qase "go.qase.io/client"
+)
+
+// MAX_FALLBACK_LOG_LENGTH is the length of the log tail put in the comment
+// when the log cannot be uploaded as an attachment.
+const MAX_FALLBACK_LOG_LENGTH = 4000
+
+var unsafeFilenameRegexp = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// attachLogs uploads the output of the test and links it to the result.
+// If the upload fails, the end of the output is put in the comment instead.
+func attachLogs(reporter QaseReporter, qaseResult *qase.ResultCreate, result ReportResult) {
+	filename := unsafeFilenameRegexp.ReplaceAllString(result.Test, "_") + ".log"
+	hash, err := reporter.UploadAttachment(ctx, config.QaseProject, filename, []byte(result.Output))
+	if err != nil {
+		printVerbose("Failed to upload log of case %d, adding it to the comment: %v\n", result.TestCaseId, err)
+		qaseResult.Comment = strings.TrimSpace(qaseResult.Comment + "\n\n" + truncateLogTail(result.Output, MAX_FALLBACK_LOG_LENGTH))
+		return
+	}
+	qaseResult.Attachments = append(qaseResult.Attachments, hash)
+}
+
+// truncateLogTail keeps the last bytes of the log, where the failure usually is,
+// without splitting a multibyte character.
+func truncateLogTail(log string, maxLength int) string {
+	if len(log) <= maxLength {
+		return log
+	}
+	start := len(log) - maxLength
+	for start < len(log) && !utf8.RuneStart(log[start]) {
+		start++
+	}
+	return fmt.Sprintf("... (truncated)\n%s", log[start:])
+}
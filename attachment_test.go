@@ -0,0 +1,69 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/require"
+	qase "go.qase.io/client"
+)
+
+func TestAttachLogs(t *testing.T) {
+	result := ReportResult{
+		TestCaseId: 1,
+		Test:       "TestFoo/QASE-1",
+		Status:     TEST_CASE_RESULT_STATUS_FAILED,
+		Output:     "=== RUN   TestFoo/QASE-1\n    foo_test.go:10: expected 1, got 2\n",
+	}
+
+	t.Run("Uploads the log as an attachment", func(t *testing.T) {
+		reporter := &fakeReporter{}
+		qaseResult := qase.ResultCreate{Comment: "Package: example.com/foo"}
+		attachLogs(reporter, &qaseResult, result)
+		require.Equal(t, []string{"hash-1"}, qaseResult.Attachments)
+		require.Equal(t, "Package: example.com/foo", qaseResult.Comment)
+		require.Equal(t, result.Output, string(reporter.attachments["hash-1"]))
+	})
+
+	t.Run("Falls back to the comment when the upload fails", func(t *testing.T) {
+		reporter := &fakeReporter{uploadAttachmentErr: errors.New("upload failed")}
+		qaseResult := qase.ResultCreate{Comment: "Package: example.com/foo"}
+		attachLogs(reporter, &qaseResult, result)
+		require.Empty(t, qaseResult.Attachments)
+		require.Contains(t, qaseResult.Comment, "Package: example.com/foo\n\n=== RUN")
+		require.Contains(t, qaseResult.Comment, "expected 1, got 2")
+	})
+}
+
+func TestNewResultCreatesFallbackLogFitsMaxCommentLength(t *testing.T) {
+	originalConfig, originalCommentTemplate := config, commentTemplate
+	defer func() { config, commentTemplate = originalConfig, originalCommentTemplate }()
+	config.QaseProject = "DEMO"
+	config.AttachLogs = true
+	config.MaxCommentLength = 100
+	var err error
+	commentTemplate, err = parseCommentTemplate(strings.Repeat("x", 100))
+	require.Nil(t, err)
+
+	result := ReportResult{
+		TestCaseId: 1,
+		Test:       "TestFoo_QASE-1",
+		Status:     TEST_CASE_RESULT_STATUS_FAILED,
+		Output:     strings.Repeat("expected 1, got 2\n", 100),
+	}
+	reporter := &fakeReporter{uploadAttachmentErr: errors.New("upload failed")}
+	qaseResults, _ := newResultCreates(reporter, []ReportResult{result})
+	require.Len(t, qaseResults, 1)
+	require.Empty(t, qaseResults[0].Attachments)
+	require.Equal(t, 100, utf8.RuneCountInString(qaseResults[0].Comment))
+	require.Contains(t, qaseResults[0].Comment, "[truncated")
+}
+
+func TestTruncateLogTail(t *testing.T) {
+	require.Equal(t, "short", truncateLogTail("short", 10))
+	require.Equal(t, "... (truncated)\n6789", truncateLogTail("0123456789", 4))
+	// Does not split the multibyte characters
+	require.Equal(t, "... (truncated)\néé", truncateLogTail("ééé", 5))
+}
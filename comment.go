@@ -0,0 +1,101 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+	"unicode/utf8"
+)
+
+// DEFAULT_MAX_COMMENT_LENGTH is the size limit of a result comment in Qase,
+// in characters. A longer comment fails the whole bulk submission.
+const DEFAULT_MAX_COMMENT_LENGTH = 65535
+
+// DEFAULT_COMMENT_TEMPLATE is the comment of a result unless --comment-template
+// is given. Each line is left out when its field is empty.
+const DEFAULT_COMMENT_TEMPLATE = `{{with .Test}}Test: {{.}}
+{{end}}{{with .Package}}Package: {{.}}
+{{end}}{{with .Status}}Status: {{.}}
+{{end}}{{with .Elapsed}}Elapsed: {{.}}
+{{end}}{{with .Owner}}Owner: {{.}}
+{{end}}{{with .Severity}}Severity: {{.}}
+{{end}}{{.Comment}}`
+
+// CommentData is the data available to the --comment-template.
+type CommentData struct {
+	Test     string
+	Package  string
+	Status   string
+	Elapsed  time.Duration
+	Owner    string
+	Severity string
+	// Comment is the comment added while processing, e.g. the benchmark metrics
+	Comment string
+}
+
+// commentTemplate renders the comment of each result, nil to leave it empty.
+var commentTemplate = template.Must(parseCommentTemplate(DEFAULT_COMMENT_TEMPLATE))
+
+// parseCommentTemplate parses the --comment-template. An empty template turns
+// the comments off.
+func parseCommentTemplate(text string) (*template.Template, error) {
+	if text == "" {
+		return nil, nil
+	}
+	tmpl, err := template.New("comment").Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	// Catch unknown fields now rather than for each result
+	if err := tmpl.Execute(&bytes.Buffer{}, CommentData{}); err != nil {
+		return nil, err
+	}
+	return tmpl, nil
+}
+
+// truncateComment cuts the comment to at most maxLength characters, ending it
+// with a note about the truncation. It never splits a multibyte character.
+func truncateComment(comment string, maxLength int) string {
+	length := utf8.RuneCountInString(comment)
+	if maxLength <= 0 || length <= maxLength {
+		return comment
+	}
+	note := fmt.Sprintf("… [truncated %d characters]", length)
+	keep := maxLength - utf8.RuneCountInString(note)
+	if keep <= 0 {
+		// Too short for the note, keep what fits
+		keep = maxLength - 1
+		note = "…"
+	}
+	runes := 0
+	for i := range comment {
+		if runes == keep {
+			return comment[:i] + note
+		}
+		runes++
+	}
+	return comment
+}
+
+func createComment(result ReportResult) string {
+	if commentTemplate == nil {
+		return ""
+	}
+	var buf bytes.Buffer
+	err := commentTemplate.Execute(&buf, CommentData{
+		Test:     result.Test,
+		Package:  result.Package,
+		Status:   result.Status,
+		Elapsed:  time.Duration(result.TimeMs) * time.Millisecond,
+		Owner:    result.Owner,
+		Severity: result.Severity,
+		Comment:  result.Comment,
+	})
+	if err != nil {
+		printVerbose("Failed to render the comment of %v: %v\n", result.Test, err)
+		return ""
+	}
+	return strings.TrimRight(buf.String(), "\n")
+}
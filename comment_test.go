@@ -0,0 +1,130 @@
+package main
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateComment(t *testing.T) {
+	originalCommentTemplate := commentTemplate
+	defer func() { commentTemplate = originalCommentTemplate }()
+
+	result := ReportResult{
+		Package:    "example.com/foo",
+		Test:       "TestFoo_QASE-1",
+		TestCaseId: 1,
+		Status:     TEST_CASE_RESULT_STATUS_FAILED,
+		TimeMs:     1500,
+		Comment:    "Parameters:\ncase_a: failed",
+	}
+
+	testcases := []struct {
+		name     string
+		template string
+		expected string
+	}{
+		{
+			name:     "Default template",
+			template: DEFAULT_COMMENT_TEMPLATE,
+			expected: "Test: TestFoo_QASE-1\nPackage: example.com/foo\nStatus: failed\nElapsed: 1.5s\nParameters:\ncase_a: failed",
+		},
+		{
+			name:     "Custom template",
+			template: "{{.Status}} in {{.Elapsed}} ({{.Package}})",
+			expected: "failed in 1.5s (example.com/foo)",
+		},
+		{
+			name:     "Empty template",
+			template: "",
+			expected: "",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			var err error
+			commentTemplate, err = parseCommentTemplate(tc.template)
+			require.Nil(t, err)
+			require.Equal(t, tc.expected, createComment(result))
+		})
+	}
+
+	t.Run("Unknown field", func(t *testing.T) {
+		_, err := parseCommentTemplate("{{.Author}}")
+		require.NotNil(t, err)
+	})
+}
+
+func TestRunCommentTemplate(t *testing.T) {
+	originalConfig, originalReporter, originalStderr, originalCtx, originalCommentTemplate := config, reporter, stderr, ctx, commentTemplate
+	defer func() {
+		config, reporter, stderr, ctx, commentTemplate = originalConfig, originalReporter, originalStderr, originalCtx, originalCommentTemplate
+	}()
+	stderr = io.Discard
+
+	// Unset, the template is the default rather than the empty flag default
+	require.Equal(t, DEFAULT_COMMENT_TEMPLATE, viper.GetString("comment_template"))
+
+	filename := filepath.Join(t.TempDir(), "report.jsonl")
+	line := `{"Action":"pass","Package":"example.com/foo","Test":"TestFoo_QASE-1","Elapsed":0.1}`
+	require.Nil(t, os.WriteFile(filename, []byte(line+"\n"), 0644))
+
+	testcases := []struct {
+		name     string
+		template string
+		expected string
+	}{
+		{name: "Custom template", template: "{{.Status}} {{.Test}}", expected: "passed TestFoo_QASE-1"},
+		{name: "Empty template", template: "", expected: ""},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			// As unmarshalled from the flag, the environment, or the config file
+			config = Config{
+				Filenames:        []string{filename},
+				QaseApiToken:     "token",
+				QaseProject:      "DEMO",
+				SkipProjectCheck: true,
+				CommentTemplate:  tc.template,
+			}
+			fake := &fakeReporter{runId: 10}
+			reporter = fake
+			require.Equal(t, EXIT_CODE_OK, run(cmd, nil))
+			require.Equal(t, tc.expected, fake.resultBulks[0][0].Comment)
+		})
+	}
+}
+
+func TestTruncateComment(t *testing.T) {
+	testcases := []struct {
+		name      string
+		comment   string
+		maxLength int
+		expected  string
+	}{
+		{name: "Short", comment: "passed", maxLength: 40, expected: "passed"},
+		{name: "No limit", comment: strings.Repeat("a", 100), maxLength: 0, expected: strings.Repeat("a", 100)},
+		{name: "ASCII", comment: strings.Repeat("a", 50), maxLength: 40, expected: strings.Repeat("a", 13) + "… [truncated 50 characters]"},
+		{name: "Multibyte at the boundary", comment: strings.Repeat("日本", 25), maxLength: 40, expected: strings.Repeat("日本", 6) + "日… [truncated 50 characters]"},
+		{name: "Multibyte exactly at the limit", comment: strings.Repeat("é", 40), maxLength: 40, expected: strings.Repeat("é", 40)},
+		{name: "Too short for the note", comment: "ééééé", maxLength: 3, expected: "éé…"},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual := truncateComment(tc.comment, tc.maxLength)
+			require.Equal(t, tc.expected, actual)
+			require.True(t, utf8.ValidString(actual))
+			if tc.maxLength > 0 {
+				require.LessOrEqual(t, utf8.RuneCountInString(actual), tc.maxLength)
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// stdinIsTerminal is replaced in tests to simulate an interactive session.
+var stdinIsTerminal = func() bool {
+	stat, err := os.Stdin.Stat()
+	if err != nil {
+		return false
+	}
+	return stat.Mode()&os.ModeCharDevice != 0
+}
+
+// confirmSubmission asks before submitting more results than the threshold
+// when running interactively. It never prompts with --yes or in CI, where
+// stdin is not a terminal.
+func confirmSubmission(count int, threshold int, yes bool, in io.Reader, out io.Writer) bool {
+	if yes || threshold <= 0 || count <= threshold || !stdinIsTerminal() {
+		return true
+	}
+	fmt.Fprintf(out, "About to submit %d results to Qase. Continue? [y/N] ", count)
+	answer, _ := bufio.NewReader(in).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
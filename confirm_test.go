@@ -0,0 +1,109 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfirmSubmission(t *testing.T) {
+	originalStdinIsTerminal := stdinIsTerminal
+	defer func() { stdinIsTerminal = originalStdinIsTerminal }()
+
+	testcases := []struct {
+		name           string
+		isTerminal     bool
+		count          int
+		yes            bool
+		answer         string
+		expected       bool
+		expectedPrompt bool
+	}{
+		{
+			name:           "TTY over threshold confirmed",
+			isTerminal:     true,
+			count:          200,
+			answer:         "y\n",
+			expected:       true,
+			expectedPrompt: true,
+		},
+		{
+			name:           "TTY over threshold declined",
+			isTerminal:     true,
+			count:          200,
+			answer:         "\n",
+			expected:       false,
+			expectedPrompt: true,
+		},
+		{
+			name:       "TTY under threshold",
+			isTerminal: true,
+			count:      50,
+			expected:   true,
+		},
+		{
+			name:       "TTY over threshold with --yes",
+			isTerminal: true,
+			count:      200,
+			yes:        true,
+			expected:   true,
+		},
+		{
+			name:       "Non-TTY over threshold",
+			isTerminal: false,
+			count:      200,
+			expected:   true,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			stdinIsTerminal = func() bool { return tc.isTerminal }
+			var out bytes.Buffer
+			actual := confirmSubmission(tc.count, 100, tc.yes, strings.NewReader(tc.answer), &out)
+			require.Equal(t, tc.expected, actual)
+			require.Equal(t, tc.expectedPrompt, strings.Contains(out.String(), "About to submit 200 results"))
+		})
+	}
+}
+
+func TestRunDeclined(t *testing.T) {
+	originalConfig, originalReporter, originalStderr, originalCtx, originalCommentTemplate := config, reporter, stderr, ctx, commentTemplate
+	originalStdin, originalStdinIsTerminal := os.Stdin, stdinIsTerminal
+	defer func() {
+		config, reporter, stderr, ctx, commentTemplate = originalConfig, originalReporter, originalStderr, originalCtx, originalCommentTemplate
+		os.Stdin, stdinIsTerminal = originalStdin, originalStdinIsTerminal
+	}()
+	stderr = io.Discard
+
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "report.jsonl")
+	lines := `{"Action":"pass","Package":"example.com/foo","Test":"TestFoo_QASE-1","Elapsed":0.1}` + "\n" +
+		`{"Action":"pass","Package":"example.com/foo","Test":"TestBar_QASE-2","Elapsed":0.1}` + "\n"
+	require.Nil(t, os.WriteFile(filename, []byte(lines), 0644))
+	answer := filepath.Join(dir, "answer")
+	require.Nil(t, os.WriteFile(answer, []byte("n\n"), 0644))
+	stdin, err := os.Open(answer)
+	require.Nil(t, err)
+	defer stdin.Close()
+	os.Stdin = stdin
+	stdinIsTerminal = func() bool { return true }
+
+	config = Config{
+		Filenames:        []string{filename},
+		QaseApiToken:     "token",
+		QaseProject:      "DEMO",
+		SkipProjectCheck: true,
+		ConfirmThreshold: 1,
+	}
+	fake := &fakeReporter{runId: 10}
+	reporter = fake
+
+	require.Equal(t, EXIT_CODE_REPORT_ERROR, run(cmd, nil))
+	require.Empty(t, fake.calls)
+}
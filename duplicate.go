@@ -0,0 +1,81 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ON_DUPLICATE_* handle the results of a case reported by several tests, e.g.
+// when tests in two packages refer to the same case, see --on-duplicate.
+const (
+	// ON_DUPLICATE_MERGE fails the case if any result failed, and lists all
+	// packages. It is the default.
+	ON_DUPLICATE_MERGE = "merge"
+	// ON_DUPLICATE_ALL submits all results, and Qase keeps the last one.
+	ON_DUPLICATE_ALL   = "all"
+	ON_DUPLICATE_FIRST = "first"
+	ON_DUPLICATE_LAST  = "last"
+	ON_DUPLICATE_ERROR = "error"
+)
+
+// resolveDuplicateResults keeps one result per case and parameter set with the
+// policy, merging them by default. It runs after the flaky and parameterized
+// results are grouped, so only results of different tests are merged.
+func resolveDuplicateResults(results []ReportResult, policy string) ([]ReportResult, error) {
+	switch policy {
+	case ON_DUPLICATE_ALL:
+		return results, nil
+	case "":
+		policy = ON_DUPLICATE_MERGE
+	case ON_DUPLICATE_MERGE, ON_DUPLICATE_FIRST, ON_DUPLICATE_LAST, ON_DUPLICATE_ERROR:
+	default:
+		return nil, fmt.Errorf("unknown duplicate policy: %v", policy)
+	}
+
+	resolved := make([]ReportResult, 0, len(results))
+	indexes := make(map[string]int)
+	for _, result := range results {
+		key := fmt.Sprintf("%d\x00%s", result.TestCaseId, result.Parameter)
+		i, ok := indexes[key]
+		if !ok {
+			indexes[key] = len(resolved)
+			resolved = append(resolved, result)
+			continue
+		}
+		switch policy {
+		case ON_DUPLICATE_MERGE:
+			status := mergeStatus(resolved[i].Status, result.Status)
+			if status != resolved[i].Status && status == result.Status {
+				// The details of the result that decides the status, e.g. the failure
+				resolved[i].Output = result.Output
+				resolved[i].Stacktrace = result.Stacktrace
+				resolved[i].Comment = result.Comment
+			}
+			resolved[i].Status = status
+			resolved[i].TimeMs += result.TimeMs
+			resolved[i].Package = mergePackages(resolved[i].Package, result.Package)
+		case ON_DUPLICATE_LAST:
+			resolved[i] = result
+		case ON_DUPLICATE_ERROR:
+			return nil, fmt.Errorf("case %d is reported by several tests: %v in %v and %v in %v",
+				result.TestCaseId, resolved[i].Test, resolved[i].Package, result.Test, result.Package)
+		}
+	}
+	return resolved, nil
+}
+
+// mergePackages adds the package to the comma separated packages, once.
+func mergePackages(packages string, pkg string) string {
+	if packages == "" {
+		return pkg
+	}
+	if pkg == "" {
+		return packages
+	}
+	for _, existing := range strings.Split(packages, ", ") {
+		if existing == pkg {
+			return packages
+		}
+	}
+	return packages + ", " + pkg
+}
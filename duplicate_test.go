@@ -0,0 +1,125 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveDuplicateResults(t *testing.T) {
+	results := []ReportResult{
+		{Package: "example.com/foo", Test: "TestA_QASE-1", TestCaseId: 1, Status: TEST_CASE_RESULT_STATUS_PASSED, TimeMs: 100},
+		{Package: "example.com/foo", Test: "TestB_QASE-2", TestCaseId: 2, Status: TEST_CASE_RESULT_STATUS_PASSED, TimeMs: 10},
+		{Package: "example.com/bar", Test: "TestC_QASE-1", TestCaseId: 1, Status: TEST_CASE_RESULT_STATUS_FAILED, TimeMs: 200, Output: "boom", Stacktrace: "bar_test.go:12", Comment: "note"},
+		{Package: "example.com/foo", Test: "TestD_QASE-2", TestCaseId: 2, Status: TEST_CASE_RESULT_STATUS_PASSED, TimeMs: 20, Parameter: "sub"},
+	}
+
+	merged := []ReportResult{
+		{Package: "example.com/foo, example.com/bar", Test: "TestA_QASE-1", TestCaseId: 1, Status: TEST_CASE_RESULT_STATUS_FAILED, TimeMs: 300, Output: "boom", Stacktrace: "bar_test.go:12", Comment: "note"},
+		results[1],
+		results[3],
+	}
+
+	testcases := []struct {
+		name          string
+		policy        string
+		expected      []ReportResult
+		expectedError string
+	}{
+		{
+			name:     "No policy merges",
+			policy:   "",
+			expected: merged,
+		},
+		{
+			name:     "Merge",
+			policy:   ON_DUPLICATE_MERGE,
+			expected: merged,
+		},
+		{
+			name:     "All",
+			policy:   ON_DUPLICATE_ALL,
+			expected: results,
+		},
+		{
+			name:     "First",
+			policy:   ON_DUPLICATE_FIRST,
+			expected: []ReportResult{results[0], results[1], results[3]},
+		},
+		{
+			name:     "Last",
+			policy:   ON_DUPLICATE_LAST,
+			expected: []ReportResult{results[2], results[1], results[3]},
+		},
+		{
+			name:          "Error",
+			policy:        ON_DUPLICATE_ERROR,
+			expectedError: "case 1 is reported by several tests: TestA_QASE-1 in example.com/foo and TestC_QASE-1 in example.com/bar",
+		},
+		{
+			name:          "Unknown policy",
+			policy:        "random",
+			expectedError: "unknown duplicate policy: random",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			input := make([]ReportResult, len(results))
+			copy(input, results)
+			actual, err := resolveDuplicateResults(input, tc.policy)
+			if tc.expectedError != "" {
+				require.EqualError(t, err, tc.expectedError)
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
+func TestMergeStatus(t *testing.T) {
+	testcases := []struct {
+		a        string
+		b        string
+		expected string
+	}{
+		{TEST_CASE_RESULT_STATUS_PASSED, TEST_CASE_RESULT_STATUS_PASSED, TEST_CASE_RESULT_STATUS_PASSED},
+		{TEST_CASE_RESULT_STATUS_PASSED, TEST_CASE_RESULT_STATUS_FAILED, TEST_CASE_RESULT_STATUS_FAILED},
+		{TEST_CASE_RESULT_STATUS_INVALID, TEST_CASE_RESULT_STATUS_FAILED, TEST_CASE_RESULT_STATUS_FAILED},
+		{TEST_CASE_RESULT_STATUS_BLOCKED, TEST_CASE_RESULT_STATUS_SKIPPED, TEST_CASE_RESULT_STATUS_BLOCKED},
+		{TEST_CASE_RESULT_STATUS_INVALID, TEST_CASE_RESULT_STATUS_PASSED, TEST_CASE_RESULT_STATUS_INVALID},
+		{TEST_CASE_RESULT_STATUS_PASSED, TEST_CASE_RESULT_STATUS_BLOCKED, TEST_CASE_RESULT_STATUS_BLOCKED},
+		{TEST_CASE_RESULT_STATUS_SKIPPED, TEST_CASE_RESULT_STATUS_PASSED, TEST_CASE_RESULT_STATUS_PASSED},
+		{TEST_CASE_RESULT_STATUS_SKIPPED, TEST_CASE_RESULT_STATUS_SKIPPED, TEST_CASE_RESULT_STATUS_SKIPPED},
+		{TEST_CASE_RESULT_STATUS_IN_PROGRESS, TEST_CASE_RESULT_STATUS_SKIPPED, TEST_CASE_RESULT_STATUS_SKIPPED},
+		{TEST_CASE_RESULT_STATUS_IN_PROGRESS, TEST_CASE_RESULT_STATUS_IN_PROGRESS, TEST_CASE_RESULT_STATUS_IN_PROGRESS},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.a+"+"+tc.b, func(t *testing.T) {
+			require.Equal(t, tc.expected, mergeStatus(tc.a, tc.b))
+			require.Equal(t, tc.expected, mergeStatus(tc.b, tc.a))
+		})
+	}
+}
+
+func TestMergePackages(t *testing.T) {
+	testcases := []struct {
+		name     string
+		packages string
+		pkg      string
+		expected string
+	}{
+		{name: "New package", packages: "example.com/foo", pkg: "example.com/bar", expected: "example.com/foo, example.com/bar"},
+		{name: "Same package", packages: "example.com/foo, example.com/bar", pkg: "example.com/bar", expected: "example.com/foo, example.com/bar"},
+		{name: "No packages yet", packages: "", pkg: "example.com/foo", expected: "example.com/foo"},
+		{name: "No package", packages: "example.com/foo", pkg: "", expected: "example.com/foo"},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, mergePackages(tc.packages, tc.pkg))
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FLAKY_AS_* is the status of a flaky case, see --flaky-as.
+const (
+	FLAKY_AS_PASSED = "passed"
+	FLAKY_AS_FAILED = "failed"
+)
+
+// applyFlakyPolicy reports a flaky test, i.e. one that both passed and failed
+// in the input, e.g. with -count or a retry wrapper, as a single result with
+// the status of the policy and a note in its comment. The result is the last
+// run with that status, for its output. Without a policy the results are kept.
+func applyFlakyPolicy(results []ReportResult, policy string) ([]ReportResult, error) {
+	switch policy {
+	case "":
+		return results, nil
+	case FLAKY_AS_PASSED, FLAKY_AS_FAILED:
+	default:
+		return nil, fmt.Errorf("unknown flaky policy: %v", policy)
+	}
+
+	// The runs of a test, as the same case in another package or test is a
+	// duplicate rather than a retry, see --on-duplicate
+	runs := make(map[string][]ReportResult)
+	for _, result := range results {
+		key := flakyKey(result)
+		runs[key] = append(runs[key], result)
+	}
+
+	kept := make([]ReportResult, 0, len(results))
+	reported := make(map[string]bool)
+	for _, result := range results {
+		key := flakyKey(result)
+		flaky, ok := reportFlaky(runs[key], policy)
+		if !ok {
+			kept = append(kept, result)
+			continue
+		}
+		if !reported[key] {
+			reported[key] = true
+			kept = append(kept, flaky)
+		}
+	}
+	return kept, nil
+}
+
+func flakyKey(result ReportResult) string {
+	return fmt.Sprintf("%d\x00%s\x00%s", result.TestCaseId, result.Package, result.Test)
+}
+
+// reportFlaky returns the result of the runs of a test per the policy, if the
+// runs both passed and failed.
+func reportFlaky(runs []ReportResult, policy string) (result ReportResult, ok bool) {
+	failed := 0
+	passed := false
+	for _, run := range runs {
+		switch run.Status {
+		case TEST_CASE_RESULT_STATUS_FAILED:
+			failed++
+		case TEST_CASE_RESULT_STATUS_PASSED:
+			passed = true
+		}
+	}
+	if failed == 0 || !passed {
+		return
+	}
+	for _, run := range runs {
+		if run.Status == policy {
+			result = run
+		}
+	}
+	note := fmt.Sprintf("Flaky: failed %d of %d runs", failed, len(runs))
+	result.Comment = strings.TrimPrefix(result.Comment+"\n"+note, "\n")
+	return result, true
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyFlakyPolicy(t *testing.T) {
+	// The case fails, then passes on the retry
+	input := strings.Join([]string{
+		`{"Action":"run","Package":"example.com/foo","Test":"TestFoo_QASE-1"}`,
+		`{"Action":"output","Package":"example.com/foo","Test":"TestFoo_QASE-1","Output":"    foo_test.go:12: connection reset\n"}`,
+		`{"Action":"fail","Package":"example.com/foo","Test":"TestFoo_QASE-1","Elapsed":0.5}`,
+		`{"Action":"run","Package":"example.com/foo","Test":"TestFoo_QASE-1"}`,
+		`{"Action":"pass","Package":"example.com/foo","Test":"TestFoo_QASE-1","Elapsed":0.25}`,
+		`{"Action":"pass","Package":"example.com/foo","Test":"TestBar_QASE-2","Elapsed":0.1}`,
+		`{"Action":"fail","Package":"example.com/bar","Test":"TestBar_QASE-2","Elapsed":0.1}`,
+	}, "\n")
+	results, err := processReader(strings.NewReader(input))
+	require.Nil(t, err)
+	require.Len(t, results, 4)
+
+	t.Run("Without policy keeps the results", func(t *testing.T) {
+		kept, err := applyFlakyPolicy(results, "")
+		require.Nil(t, err)
+		require.Equal(t, results, kept)
+	})
+
+	t.Run("Flaky as passed", func(t *testing.T) {
+		kept, err := applyFlakyPolicy(results, FLAKY_AS_PASSED)
+		require.Nil(t, err)
+		require.Len(t, kept, 3)
+		require.Equal(t, int64(1), kept[0].TestCaseId)
+		require.Equal(t, TEST_CASE_RESULT_STATUS_PASSED, kept[0].Status)
+		require.Equal(t, int64(250), kept[0].TimeMs)
+		require.Equal(t, "Flaky: failed 1 of 2 runs", kept[0].Comment)
+		// The same case in another package is not a retry
+		require.Equal(t, results[2:], kept[1:])
+	})
+
+	t.Run("Flaky as failed", func(t *testing.T) {
+		kept, err := applyFlakyPolicy(results, FLAKY_AS_FAILED)
+		require.Nil(t, err)
+		require.Len(t, kept, 3)
+		require.Equal(t, TEST_CASE_RESULT_STATUS_FAILED, kept[0].Status)
+		require.Contains(t, kept[0].Output, "connection reset")
+		require.Equal(t, "Flaky: failed 1 of 2 runs", kept[0].Comment)
+		require.Contains(t, createComment(kept[0]), "Flaky: failed 1 of 2 runs")
+	})
+
+	t.Run("Unknown policy", func(t *testing.T) {
+		_, err := applyFlakyPolicy(results, "skipped")
+		require.ErrorContains(t, err, "unknown flaky policy")
+	})
+}
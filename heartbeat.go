@@ -0,0 +1,37 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// startHeartbeat prints a line every interval until stopped, for the CI
+// systems that kill jobs without output. It does nothing without an interval.
+func startHeartbeat(w io.Writer, interval time.Duration) (stop func()) {
+	if interval <= 0 {
+		return func() {}
+	}
+	start := time.Now()
+	ticker := time.NewTicker(interval)
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-done:
+				return
+			case tick := <-ticker.C:
+				fmt.Fprintf(w, "Still reporting to Qase, %v elapsed\n", tick.Sub(start).Round(time.Second))
+			}
+		}
+	}()
+	return func() {
+		ticker.Stop()
+		close(done)
+		wg.Wait()
+	}
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// syncBuffer is a bytes.Buffer safe to write from the heartbeat goroutine.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestStartHeartbeat(t *testing.T) {
+	originalConfig := config
+	defer func() { config = originalConfig }()
+	config.QaseProject = "DEMO"
+
+	t.Run("Emits heartbeats during a slow submission", func(t *testing.T) {
+		var buf syncBuffer
+		stop := startHeartbeat(&buf, 10*time.Millisecond)
+		_, err := runReport(&fakeReporter{runId: 10, delay: 20 * time.Millisecond}, nil, []ReportResult{{TestCaseId: 1}})
+		stop()
+		require.Nil(t, err)
+
+		heartbeats := strings.Count(buf.String(), "Still reporting to Qase")
+		require.GreaterOrEqual(t, heartbeats, 2)
+
+		// No more heartbeats once stopped
+		time.Sleep(30 * time.Millisecond)
+		require.Equal(t, heartbeats, strings.Count(buf.String(), "Still reporting to Qase"))
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		var buf syncBuffer
+		stop := startHeartbeat(&buf, 0)
+		time.Sleep(10 * time.Millisecond)
+		stop()
+		require.Equal(t, "", buf.String())
+	})
+}
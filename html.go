@@ -0,0 +1,64 @@
+package main
+
+import (
+	"html/template"
+	"io"
+)
+
+// htmlReportTemplate is a self-contained page, so the report can be archived
+// or shared as a single file. Clicking a column header sorts the table.
+var htmlReportTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Qase Run {{.RunId}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #ccc; padding: 0.4em 0.8em; text-align: left; }
+th { cursor: pointer; background: #f4f4f4; }
+.passed { color: #2e7d32; }
+.failed { color: #c62828; }
+</style>
+</head>
+<body>
+<h1>Qase Run <a href="{{.RunUrl}}">{{.RunId}}</a></h1>
+<ul id="counts">
+<li>Total: {{.Counts.Total}}</li>
+<li>Passed: {{.Counts.Passed}}</li>
+<li>Failed: {{.Counts.Failed}}</li>
+<li>Skipped: {{.Counts.Skipped}}</li>
+</ul>
+<table id="results">
+<thead>
+<tr><th>Case ID</th><th>Status</th></tr>
+</thead>
+<tbody>
+{{- range .TestRuns}}
+<tr><td><a href="{{.TestCaseUrl}}">{{.TestCaseId}}</a></td><td class="{{.Status}}">{{.Status}}</td></tr>
+{{- end}}
+</tbody>
+</table>
+<script>
+document.querySelectorAll("#results th").forEach(function (th, column) {
+  var ascending = true;
+  th.addEventListener("click", function () {
+    var tbody = document.querySelector("#results tbody");
+    var rows = Array.from(tbody.rows);
+    rows.sort(function (a, b) {
+      var x = a.cells[column].innerText, y = b.cells[column].innerText;
+      var result = isNaN(x) || isNaN(y) ? x.localeCompare(y) : x - y;
+      return ascending ? result : -result;
+    });
+    ascending = !ascending;
+    rows.forEach(function (row) { tbody.appendChild(row); });
+  });
+});
+</script>
+</body>
+</html>
+`))
+
+func writeHtmlOutput(w io.Writer, output ReportOutput) error {
+	return htmlReportTemplate.Execute(w, output)
+}
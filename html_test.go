@@ -0,0 +1,36 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteHtmlOutput(t *testing.T) {
+	output := ReportOutput{
+		RunId:  10,
+		RunUrl: "https://app.qase.io/run/DEMO/dashboard/10",
+		Counts: ReportOutputCounts{Passed: 2, Failed: 1, Total: 3},
+		TestRuns: []ReportOutputTestRun{
+			{TestCaseId: 1, TestCaseUrl: "https://app.qase.io/case/DEMO-1", Status: TEST_CASE_RESULT_STATUS_PASSED},
+			{TestCaseId: 2, TestCaseUrl: "https://app.qase.io/case/DEMO-2", Status: TEST_CASE_RESULT_STATUS_FAILED},
+			{TestCaseId: 3, TestCaseUrl: "https://app.qase.io/case/DEMO-3", Status: TEST_CASE_RESULT_STATUS_PASSED},
+		},
+	}
+
+	var buf bytes.Buffer
+	err := writeHtmlOutput(&buf, output)
+	require.Nil(t, err)
+
+	html := buf.String()
+	require.Contains(t, html, "<!DOCTYPE html>")
+	require.Contains(t, html, `<a href="https://app.qase.io/run/DEMO/dashboard/10">10</a>`)
+	require.Contains(t, html, `<table id="results">`)
+	require.Contains(t, html, `<tr><td><a href="https://app.qase.io/case/DEMO-2">2</a></td><td class="failed">failed</td></tr>`)
+	require.Contains(t, html, "<li>Total: 3</li>")
+	require.Contains(t, html, "<li>Passed: 2</li>")
+	require.Contains(t, html, "<li>Failed: 1</li>")
+	require.Contains(t, html, "<li>Skipped: 0</li>")
+	require.Contains(t, html, "<script>")
+}
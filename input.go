@@ -0,0 +1,186 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const (
+	// INPUT_FORMAT_TEST2JSON is the output of `go test -json`.
+	INPUT_FORMAT_TEST2JSON = "test2json"
+	// INPUT_FORMAT_SIMPLE has a `CASEID STATUS [TIME_MS]` line per result.
+	INPUT_FORMAT_SIMPLE = "simple"
+)
+
+// inputParsers reads the results of each --input-format.
+var inputParsers = map[string]func(reader io.Reader) ([]ReportResult, error){
+	INPUT_FORMAT_TEST2JSON: processReader,
+	INPUT_FORMAT_SIMPLE:    processSimpleReader,
+}
+
+func getInputParser(format string) (func(reader io.Reader) ([]ReportResult, error), error) {
+	if format == "" {
+		format = INPUT_FORMAT_TEST2JSON
+	}
+	parser, ok := inputParsers[format]
+	if !ok {
+		return nil, fmt.Errorf("unknown input format: %v", format)
+	}
+	return parser, nil
+}
+
+// expandInputPaths turns the arguments into the list of files to process.
+// A directory is expanded to the *.jsonl files in it, including subdirectories
+// when recursive, and a glob pattern like results/*.jsonl to the matching files.
+func expandInputPaths(paths []string, recursive bool) ([]string, error) {
+	filenames := make([]string, 0, len(paths))
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err == nil && info.IsDir() {
+			dirFilenames, err := findJsonlFiles(path, recursive)
+			if err != nil {
+				return nil, err
+			}
+			if len(dirFilenames) == 0 {
+				return nil, fmt.Errorf("no .jsonl files found in directory: %v", path)
+			}
+			filenames = append(filenames, dirFilenames...)
+			continue
+		}
+		if err != nil && strings.ContainsAny(path, "*?[") {
+			matches, err := filepath.Glob(path)
+			if err != nil {
+				return nil, fmt.Errorf("invalid glob pattern %v: %v", path, err)
+			}
+			if len(matches) == 0 {
+				return nil, fmt.Errorf("no files match the pattern: %v", path)
+			}
+			filenames = append(filenames, matches...)
+			continue
+		}
+		// Missing files are reported when processing them
+		filenames = append(filenames, path)
+	}
+	return filenames, nil
+}
+
+func findJsonlFiles(dir string, recursive bool) ([]string, error) {
+	filenames := make([]string, 0)
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			if path != dir && !recursive {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if strings.HasSuffix(entry.Name(), ".jsonl") || strings.HasSuffix(entry.Name(), ".jsonl.gz") {
+			filenames = append(filenames, path)
+		}
+		return nil
+	})
+	return filenames, err
+}
+
+// gzipMagic starts every gzip stream.
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// decompressReader decompresses a gzip input, e.g. a .jsonl.gz file archived
+// by the CI. It is detected by the magic bytes, so it also works for stdin.
+// Other inputs are read as they are.
+func decompressReader(reader io.Reader) (io.Reader, error) {
+	buffered := bufio.NewReader(reader)
+	magic, err := buffered.Peek(len(gzipMagic))
+	if err != nil || !bytes.Equal(magic, gzipMagic) {
+		// Too short to be gzip, let the parser handle it
+		return buffered, nil
+	}
+	gzipReader, err := gzip.NewReader(buffered)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read gzip: %v", err)
+	}
+	return gzipReader, nil
+}
+
+// processSimpleReader reads a `CASEID STATUS [TIME_MS]` line per result, e.g.
+// "123 passed 250" or "QASE-124 failed". Empty lines and # comments are ignored.
+func processSimpleReader(reader io.Reader) ([]ReportResult, error) {
+	results := make([]ReportResult, 0)
+	scanner := bufio.NewScanner(reader)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		result, err := processSimpleLine(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", lineNumber, err)
+		}
+		results = append(results, result)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read file: %v", err)
+	}
+	return results, nil
+}
+
+func processSimpleLine(line string) (result ReportResult, err error) {
+	fields := strings.Fields(line)
+	if len(fields) < 2 || len(fields) > 3 {
+		return result, fmt.Errorf("expected CASEID STATUS [TIME_MS], got %q", line)
+	}
+	result.TestCaseId, err = strconv.ParseInt(strings.TrimPrefix(fields[0], "QASE-"), 10, 64)
+	if err != nil || result.TestCaseId <= 0 {
+		return result, fmt.Errorf("invalid case ID: %v", fields[0])
+	}
+	switch fields[1] {
+	case TEST_CASE_RESULT_STATUS_PASSED, "pass":
+		result.Status = TEST_CASE_RESULT_STATUS_PASSED
+	case TEST_CASE_RESULT_STATUS_FAILED, "fail":
+		result.Status = TEST_CASE_RESULT_STATUS_FAILED
+	case TEST_CASE_RESULT_STATUS_SKIPPED, "skip":
+		result.Status = TEST_CASE_RESULT_STATUS_SKIPPED
+	default:
+		return result, fmt.Errorf("unknown status: %v", fields[1])
+	}
+	if len(fields) == 3 {
+		result.TimeMs, err = strconv.ParseInt(fields[2], 10, 64)
+		if err != nil || result.TimeMs < 0 {
+			return result, fmt.Errorf("invalid time: %v", fields[2])
+		}
+	}
+	return result, nil
+}
+
+// hashInputFiles returns a short hash of the contents of the files, so the same
+// input gives the same hash, see --title-hash.
+func hashInputFiles(filenames []string) (string, error) {
+	hash := sha256.New()
+	for _, filename := range filenames {
+		if filename == "-" {
+			return "", fmt.Errorf("cannot hash the input from stdin")
+		}
+		file, err := os.Open(filename)
+		if err != nil {
+			return "", err
+		}
+		_, err = io.Copy(hash, file)
+		file.Close()
+		if err != nil {
+			return "", err
+		}
+	}
+	return hex.EncodeToString(hash.Sum(nil))[:8], nil
+}
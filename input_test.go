@@ -0,0 +1,193 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpandInputPaths(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{
+		"a.jsonl",
+		"b.jsonl",
+		"notes.txt",
+		"nested/c.jsonl",
+		"nested/deeper/d.jsonl",
+	} {
+		path := filepath.Join(dir, name)
+		require.Nil(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.Nil(t, os.WriteFile(path, []byte("{}\n"), 0o644))
+	}
+
+	testcases := []struct {
+		name      string
+		paths     []string
+		recursive bool
+		expected  []string
+	}{
+		{
+			name:     "Plain file",
+			paths:    []string{filepath.Join(dir, "a.jsonl")},
+			expected: []string{"a.jsonl"},
+		},
+		{
+			name:     "Directory without recursion",
+			paths:    []string{dir},
+			expected: []string{"a.jsonl", "b.jsonl"},
+		},
+		{
+			name:      "Directory with recursion",
+			paths:     []string{dir},
+			recursive: true,
+			expected:  []string{"a.jsonl", "b.jsonl", "nested/c.jsonl", "nested/deeper/d.jsonl"},
+		},
+		{
+			name:     "Glob pattern",
+			paths:    []string{filepath.Join(dir, "nested", "*", "*.jsonl")},
+			expected: []string{"nested/deeper/d.jsonl"},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := expandInputPaths(tc.paths, tc.recursive)
+			require.Nil(t, err)
+			expected := make([]string, 0)
+			for _, name := range tc.expected {
+				expected = append(expected, filepath.Join(dir, filepath.FromSlash(name)))
+			}
+			require.Equal(t, expected, actual)
+		})
+	}
+
+	t.Run("Glob without matches", func(t *testing.T) {
+		_, err := expandInputPaths([]string{filepath.Join(dir, "*.json")}, false)
+		require.NotNil(t, err)
+	})
+}
+
+func TestProcessSimpleReader(t *testing.T) {
+	input := strings.Join([]string{
+		"# case status time_ms",
+		"123 passed 250",
+		"",
+		"QASE-124 failed",
+		"125 skip",
+	}, "\n")
+
+	results, err := processSimpleReader(strings.NewReader(input))
+	require.Nil(t, err)
+	require.Equal(t, []ReportResult{
+		{TestCaseId: 123, Status: TEST_CASE_RESULT_STATUS_PASSED, TimeMs: 250},
+		{TestCaseId: 124, Status: TEST_CASE_RESULT_STATUS_FAILED},
+		{TestCaseId: 125, Status: TEST_CASE_RESULT_STATUS_SKIPPED},
+	}, results)
+
+	testcases := []struct {
+		name          string
+		input         string
+		expectedError string
+	}{
+		{name: "Missing status", input: "123", expectedError: `line 1: expected CASEID STATUS [TIME_MS], got "123"`},
+		{name: "Invalid case ID", input: "abc passed", expectedError: "line 1: invalid case ID: abc"},
+		{name: "Unknown status", input: "123 blocked", expectedError: "line 1: unknown status: blocked"},
+		{name: "Invalid time", input: "123 passed\n124 passed 1s", expectedError: "line 2: invalid time: 1s"},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := processSimpleReader(strings.NewReader(tc.input))
+			require.EqualError(t, err, tc.expectedError)
+		})
+	}
+}
+
+func TestProcessFileWithInputFormat(t *testing.T) {
+	originalConfig := config
+	defer func() { config = originalConfig }()
+
+	path := filepath.Join(t.TempDir(), "results.txt")
+	require.Nil(t, os.WriteFile(path, []byte("123 passed\n"), 0o644))
+
+	config.InputFormat = INPUT_FORMAT_SIMPLE
+	results, err := processFile(path)
+	require.Nil(t, err)
+	require.Equal(t, []ReportResult{{TestCaseId: 123, Status: TEST_CASE_RESULT_STATUS_PASSED}}, results)
+
+	config.InputFormat = "xml"
+	_, err = processFile(path)
+	require.EqualError(t, err, "unknown input format: xml")
+}
+
+func TestProcessFileGzip(t *testing.T) {
+	input := strings.Join([]string{
+		`{"Action":"pass","Package":"example.com/foo","Test":"TestFoo_QASE-1","Elapsed":0.1}`,
+		`{"Action":"fail","Package":"example.com/foo","Test":"TestBar_QASE-2","Elapsed":0.2}`,
+	}, "\n")
+	dir := t.TempDir()
+
+	var compressed bytes.Buffer
+	gzipWriter := gzip.NewWriter(&compressed)
+	_, err := gzipWriter.Write([]byte(input))
+	require.Nil(t, err)
+	require.Nil(t, gzipWriter.Close())
+	gzipPath := filepath.Join(dir, "results.jsonl.gz")
+	require.Nil(t, os.WriteFile(gzipPath, compressed.Bytes(), 0o644))
+
+	plainPath := filepath.Join(dir, "results.jsonl")
+	require.Nil(t, os.WriteFile(plainPath, []byte(input), 0o644))
+
+	for _, path := range []string{gzipPath, plainPath} {
+		t.Run(filepath.Base(path), func(t *testing.T) {
+			results, err := processFile(path)
+			require.Nil(t, err)
+			require.Len(t, results, 2)
+			require.Equal(t, int64(1), results[0].TestCaseId)
+			require.Equal(t, TEST_CASE_RESULT_STATUS_FAILED, results[1].Status)
+		})
+	}
+
+	t.Run("Corrupted gzip", func(t *testing.T) {
+		path := filepath.Join(dir, "corrupted.jsonl.gz")
+		require.Nil(t, os.WriteFile(path, compressed.Bytes()[:4], 0o644))
+		_, err := processFile(path)
+		require.NotNil(t, err)
+	})
+}
+
+func TestHashInputFiles(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name string, content string) string {
+		path := filepath.Join(dir, name)
+		require.Nil(t, os.WriteFile(path, []byte(content), 0o644))
+		return path
+	}
+	a := write("a.jsonl", `{"Action":"pass","Test":"TestFoo_QASE-1"}`+"\n")
+	b := write("b.jsonl", `{"Action":"pass","Test":"TestFoo_QASE-1"}`+"\n")
+	c := write("c.jsonl", `{"Action":"fail","Test":"TestFoo_QASE-1"}`+"\n")
+
+	hashA, err := hashInputFiles([]string{a})
+	require.Nil(t, err)
+	require.Len(t, hashA, 8)
+
+	hashB, err := hashInputFiles([]string{b})
+	require.Nil(t, err)
+	require.Equal(t, hashA, hashB)
+
+	hashC, err := hashInputFiles([]string{c})
+	require.Nil(t, err)
+	require.NotEqual(t, hashA, hashC)
+
+	hashAC, err := hashInputFiles([]string{a, c})
+	require.Nil(t, err)
+	require.NotEqual(t, hashA, hashAC)
+
+	_, err = hashInputFiles([]string{"-"})
+	require.NotNil(t, err)
+}
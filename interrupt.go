@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+// EXIT_CODE_INTERRUPTED is the exit code after SIGINT or SIGTERM, as of a
+// shell killed by SIGINT.
+const EXIT_CODE_INTERRUPTED = 130
+
+// DEFAULT_INTERRUPT_CLEANUP_TIMEOUT bounds the reporting after an interrupt
+// when --timeout is not set, so a stuck API call does not keep the CI waiting.
+const DEFAULT_INTERRUPT_CLEANUP_TIMEOUT = 30 * time.Second
+
+var (
+	// notifySignals and stopSignals are replaced in tests to send fake signals.
+	notifySignals = signal.Notify
+	stopSignals   = signal.Stop
+
+	// interrupted is set once the command got SIGINT or SIGTERM.
+	interrupted atomic.Bool
+
+	// baseCtx is the parent of the contexts of the API calls. It is canceled
+	// when the cleanup after an interrupt runs out of time.
+	baseCtx       = context.Background()
+	cancelBaseCtx = func() {}
+)
+
+// interruptCleanupTimeout is how long the results are still submitted and the
+// run completed after an interrupt.
+func interruptCleanupTimeout(timeout time.Duration) time.Duration {
+	if timeout > 0 {
+		return timeout
+	}
+	return DEFAULT_INTERRUPT_CLEANUP_TIMEOUT
+}
+
+// handleInterrupts keeps the command running on SIGINT or SIGTERM, e.g. when
+// CI cancels the job, so the results read so far are submitted and the run is
+// completed rather than left open. The API calls are canceled once the cleanup
+// timeout passes, or right away on a second signal.
+func handleInterrupts(cleanupTimeout time.Duration) (stop func()) {
+	interrupted.Store(false)
+	baseCtx, cancelBaseCtx = context.WithCancel(context.Background())
+	cancel := cancelBaseCtx
+
+	signals := make(chan os.Signal, 2)
+	notifySignals(signals, os.Interrupt, syscall.SIGTERM)
+	var timer *time.Timer
+	onSignal := func(sig os.Signal) {
+		if interrupted.Swap(true) {
+			fmt.Fprintf(stderr, "Got %v again, aborting\n", sig)
+			cancel()
+			return
+		}
+		fmt.Fprintf(stderr, "Got %v, submitting the results and completing the run within %v\n", sig, cleanupTimeout)
+		timer = time.AfterFunc(cleanupTimeout, cancel)
+	}
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-done:
+				return
+			case sig := <-signals:
+				onSignal(sig)
+			}
+		}
+	}()
+	return func() {
+		stopSignals(signals)
+		close(done)
+		wg.Wait()
+		// A signal not handled yet still makes the command exit as interrupted
+		select {
+		case <-signals:
+			interrupted.Store(true)
+		default:
+		}
+		if timer != nil {
+			timer.Stop()
+		}
+	}
+}
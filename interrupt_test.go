@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSignals replaces signal.Notify, returning the channel the command
+// listens on to send it signals.
+func fakeSignals(t *testing.T) <-chan chan<- os.Signal {
+	originalNotify, originalStop := notifySignals, stopSignals
+	originalBaseCtx, originalCancel := baseCtx, cancelBaseCtx
+	t.Cleanup(func() {
+		notifySignals, stopSignals = originalNotify, originalStop
+		baseCtx, cancelBaseCtx = originalBaseCtx, originalCancel
+		interrupted.Store(false)
+	})
+	registered := make(chan chan<- os.Signal, 1)
+	notifySignals = func(c chan<- os.Signal, sig ...os.Signal) {
+		registered <- c
+	}
+	stopSignals = func(c chan<- os.Signal) {}
+	return registered
+}
+
+func TestHandleInterrupts(t *testing.T) {
+	originalStderr := stderr
+	defer func() { stderr = originalStderr }()
+	var buf syncBuffer
+	stderr = &buf
+
+	t.Run("Cleanup times out", func(t *testing.T) {
+		registered := fakeSignals(t)
+		stop := handleInterrupts(20 * time.Millisecond)
+		defer stop()
+		signals := <-registered
+		require.False(t, interrupted.Load())
+
+		signals <- syscall.SIGINT
+		require.Eventually(t, interrupted.Load, time.Second, time.Millisecond)
+		require.Nil(t, baseCtx.Err())
+		require.Eventually(t, func() bool { return baseCtx.Err() != nil }, time.Second, time.Millisecond)
+		require.Contains(t, buf.String(), "submitting the results and completing the run within 20ms")
+	})
+
+	t.Run("Second signal aborts", func(t *testing.T) {
+		registered := fakeSignals(t)
+		stop := handleInterrupts(time.Hour)
+		defer stop()
+		signals := <-registered
+
+		signals <- syscall.SIGTERM
+		signals <- syscall.SIGTERM
+		require.Eventually(t, func() bool { return baseCtx.Err() != nil }, time.Second, time.Millisecond)
+		require.ErrorIs(t, baseCtx.Err(), context.Canceled)
+		require.Contains(t, buf.String(), "again, aborting")
+	})
+}
+
+func TestRunInterrupted(t *testing.T) {
+	originalConfig, originalReporter, originalStderr, originalCtx, originalCommentTemplate := config, reporter, stderr, ctx, commentTemplate
+	defer func() {
+		config, reporter, stderr, ctx, commentTemplate = originalConfig, originalReporter, originalStderr, originalCtx, originalCommentTemplate
+	}()
+	registered := fakeSignals(t)
+
+	filename := filepath.Join(t.TempDir(), "report.jsonl")
+	line := `{"Action":"pass","Package":"example.com/foo","Test":"TestFoo_QASE-1","Elapsed":0.1}`
+	require.Nil(t, os.WriteFile(filename, []byte(line+"\n"), 0644))
+	config = Config{
+		Filenames:        []string{filename},
+		QaseApiToken:     "token",
+		QaseProject:      "DEMO",
+		SkipProjectCheck: true,
+		Timeout:          time.Second,
+	}
+	// The signal arrives while the command is busy reporting
+	fake := &fakeReporter{runId: 10, delay: 10 * time.Millisecond}
+	reporter = fake
+	stderr = io.Discard
+	go func() {
+		signals := <-registered
+		signals <- syscall.SIGINT
+	}()
+
+	require.Equal(t, EXIT_CODE_INTERRUPTED, run(cmd, nil))
+	require.Equal(t, []string{"CreateRun", "CreateResultBulk", "CompleteRun"}, fake.calls)
+}
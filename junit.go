@@ -0,0 +1,121 @@
+package main
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+)
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	Time      string          `xml:"time,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name       string          `xml:"name,attr"`
+	ClassName  string          `xml:"classname,attr"`
+	Time       string          `xml:"time,attr"`
+	Properties []junitProperty `xml:"properties>property,omitempty"`
+	Failure    *junitFailure   `xml:"failure,omitempty"`
+	Skipped    *struct{}       `xml:"skipped,omitempty"`
+}
+
+type junitProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Content string `xml:",chardata"`
+}
+
+// writeJunitFile writes the results as JUnit XML for the CI tools that do not
+// read Qase, see writeJunit.
+func writeJunitFile(path string, results []ReportResult) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	if err := writeJunit(file, results); err != nil {
+		return err
+	}
+	return file.Close()
+}
+
+// writeJunit writes a test suite per package with a test case per result.
+func writeJunit(w io.Writer, results []ReportResult) error {
+	suites := make([]junitTestSuite, 0)
+	suiteIndex := make(map[string]int)
+	var totalMs int64
+	suiteMs := make(map[string]int64)
+	for _, result := range results {
+		i, ok := suiteIndex[result.Package]
+		if !ok {
+			i = len(suites)
+			suiteIndex[result.Package] = i
+			suites = append(suites, junitTestSuite{Name: result.Package})
+		}
+		suite := &suites[i]
+
+		testCase := junitTestCase{
+			Name:      result.Test,
+			ClassName: result.Package,
+			Time:      formatJunitTime(result.TimeMs),
+			Properties: []junitProperty{
+				{Name: "qase_case_id", Value: fmt.Sprint(result.TestCaseId)},
+			},
+		}
+		switch result.Status {
+		case TEST_CASE_RESULT_STATUS_FAILED:
+			testCase.Failure = &junitFailure{Message: "failed", Content: result.Output}
+			suite.Failures++
+		case TEST_CASE_RESULT_STATUS_SKIPPED, TEST_CASE_RESULT_STATUS_BLOCKED, TEST_CASE_RESULT_STATUS_INVALID:
+			testCase.Skipped = &struct{}{}
+			suite.Skipped++
+		}
+		suite.Tests++
+		suite.TestCases = append(suite.TestCases, testCase)
+		suiteMs[result.Package] += result.TimeMs
+		totalMs += result.TimeMs
+	}
+
+	testSuites := junitTestSuites{Suites: suites, Time: formatJunitTime(totalMs)}
+	for i := range suites {
+		suites[i].Time = formatJunitTime(suiteMs[suites[i].Name])
+		testSuites.Tests += suites[i].Tests
+		testSuites.Failures += suites[i].Failures
+		testSuites.Skipped += suites[i].Skipped
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(testSuites); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// formatJunitTime formats the milliseconds as the seconds JUnit expects.
+func formatJunitTime(ms int64) string {
+	return fmt.Sprintf("%.3f", float64(ms)/1000)
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"bytes"
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteJunit(t *testing.T) {
+	results := []ReportResult{
+		{Package: "example.com/foo", Test: "TestA_QASE-1", TestCaseId: 1, Status: TEST_CASE_RESULT_STATUS_PASSED, TimeMs: 1500},
+		{Package: "example.com/foo", Test: "TestB_QASE-2", TestCaseId: 2, Status: TEST_CASE_RESULT_STATUS_FAILED, TimeMs: 250, Output: "foo_test.go:12: expected 1, got 2\n"},
+		{Package: "example.com/bar", Test: "TestC_QASE-3", TestCaseId: 3, Status: TEST_CASE_RESULT_STATUS_SKIPPED},
+	}
+
+	var buf bytes.Buffer
+	err := writeJunit(&buf, results)
+	require.Nil(t, err)
+
+	var actual junitTestSuites
+	err = xml.Unmarshal(buf.Bytes(), &actual)
+	require.Nil(t, err)
+	require.Equal(t, "testsuites", actual.XMLName.Local)
+	require.Equal(t, 3, actual.Tests)
+	require.Equal(t, 1, actual.Failures)
+	require.Equal(t, 1, actual.Skipped)
+	require.Equal(t, "1.750", actual.Time)
+
+	require.Len(t, actual.Suites, 2)
+	foo := actual.Suites[0]
+	require.Equal(t, "example.com/foo", foo.Name)
+	require.Equal(t, 2, foo.Tests)
+	require.Equal(t, 1, foo.Failures)
+	require.Equal(t, "1.750", foo.Time)
+	require.Len(t, foo.TestCases, 2)
+	require.Equal(t, "TestA_QASE-1", foo.TestCases[0].Name)
+	require.Equal(t, "example.com/foo", foo.TestCases[0].ClassName)
+	require.Equal(t, "1.500", foo.TestCases[0].Time)
+	require.Equal(t, []junitProperty{{Name: "qase_case_id", Value: "1"}}, foo.TestCases[0].Properties)
+	require.Nil(t, foo.TestCases[0].Failure)
+	require.NotNil(t, foo.TestCases[1].Failure)
+	require.Equal(t, "foo_test.go:12: expected 1, got 2\n", foo.TestCases[1].Failure.Content)
+
+	bar := actual.Suites[1]
+	require.Equal(t, "example.com/bar", bar.Name)
+	require.Equal(t, 1, bar.Skipped)
+	require.NotNil(t, bar.TestCases[0].Skipped)
+}
+
+func TestWriteJunitFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "junit.xml")
+	err := writeJunitFile(path, []ReportResult{
+		{Package: "example.com/foo", Test: "TestA_QASE-1", TestCaseId: 1, Status: TEST_CASE_RESULT_STATUS_PASSED},
+	})
+	require.Nil(t, err)
+
+	content, err := os.ReadFile(path)
+	require.Nil(t, err)
+	require.True(t, bytes.HasPrefix(content, []byte(xml.Header+"<testsuites ")))
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// Metrics summarizes the reporting for --metrics-file, so CI can scrape the
+// health of the reporter.
+type Metrics struct {
+	Counts ReportOutputCounts
+	// TestsDuration is the elapsed time of the reported tests together
+	TestsDuration time.Duration
+	// Duration is the time taken by the reporting
+	Duration time.Duration
+	Api      ApiStats
+}
+
+func newMetrics(output ReportOutput, results []ReportResult, duration time.Duration, api ApiStats) Metrics {
+	var testsMs int64
+	for _, result := range results {
+		testsMs += result.TimeMs
+	}
+	return Metrics{
+		Counts:        output.Counts,
+		TestsDuration: time.Duration(testsMs) * time.Millisecond,
+		Duration:      duration,
+		Api:           api,
+	}
+}
+
+func writeMetricsFile(path string, metrics Metrics) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	if err := writeMetrics(file, metrics); err != nil {
+		return err
+	}
+	return file.Close()
+}
+
+// writeMetrics writes the metrics in the Prometheus text exposition format.
+func writeMetrics(w io.Writer, metrics Metrics) error {
+	statuses := []struct {
+		status string
+		count  int
+	}{
+		{TEST_CASE_RESULT_STATUS_PASSED, metrics.Counts.Passed},
+		{TEST_CASE_RESULT_STATUS_FAILED, metrics.Counts.Failed},
+		{TEST_CASE_RESULT_STATUS_SKIPPED, metrics.Counts.Skipped},
+		{TEST_CASE_RESULT_STATUS_BLOCKED, metrics.Counts.Blocked},
+		{TEST_CASE_RESULT_STATUS_INVALID, metrics.Counts.Invalid},
+	}
+	// printf writes a line unless a previous one failed, keeping the first error
+	var err error
+	printf := func(format string, a ...any) {
+		if err == nil {
+			_, err = fmt.Fprintf(w, format+"\n", a...)
+		}
+	}
+	printf("# HELP qase_reporter_results Number of reported results by status.")
+	printf("# TYPE qase_reporter_results gauge")
+	for _, s := range statuses {
+		printf("qase_reporter_results{status=%q} %d", s.status, s.count)
+	}
+	printf("# HELP qase_reporter_tests_duration_seconds Elapsed time of the reported tests.")
+	printf("# TYPE qase_reporter_tests_duration_seconds gauge")
+	printf("qase_reporter_tests_duration_seconds %v", metrics.TestsDuration.Seconds())
+	printf("# HELP qase_reporter_duration_seconds Time taken by the reporting.")
+	printf("# TYPE qase_reporter_duration_seconds gauge")
+	printf("qase_reporter_duration_seconds %v", metrics.Duration.Seconds())
+	printf("# HELP qase_reporter_api_calls_total Number of Qase API calls, retries included.")
+	printf("# TYPE qase_reporter_api_calls_total counter")
+	printf("qase_reporter_api_calls_total %d", metrics.Api.Calls)
+	printf("# HELP qase_reporter_api_retries_total Number of Qase API calls retried after being rate limited.")
+	printf("# TYPE qase_reporter_api_retries_total counter")
+	printf("qase_reporter_api_retries_total %d", metrics.Api.Retries)
+	return err
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteMetrics(t *testing.T) {
+	output := ReportOutput{Counts: ReportOutputCounts{Passed: 3, Failed: 1, Skipped: 2, Total: 6}}
+	results := []ReportResult{
+		{TestCaseId: 1, TimeMs: 1500},
+		{TestCaseId: 2, TimeMs: 250},
+	}
+	metrics := newMetrics(output, results, 2*time.Second, ApiStats{Calls: 4, Retries: 1})
+
+	var buf strings.Builder
+	require.Nil(t, writeMetrics(&buf, metrics))
+	expected := `# HELP qase_reporter_results Number of reported results by status.
+# TYPE qase_reporter_results gauge
+qase_reporter_results{status="passed"} 3
+qase_reporter_results{status="failed"} 1
+qase_reporter_results{status="skipped"} 2
+qase_reporter_results{status="blocked"} 0
+qase_reporter_results{status="invalid"} 0
+# HELP qase_reporter_tests_duration_seconds Elapsed time of the reported tests.
+# TYPE qase_reporter_tests_duration_seconds gauge
+qase_reporter_tests_duration_seconds 1.75
+# HELP qase_reporter_duration_seconds Time taken by the reporting.
+# TYPE qase_reporter_duration_seconds gauge
+qase_reporter_duration_seconds 2
+# HELP qase_reporter_api_calls_total Number of Qase API calls, retries included.
+# TYPE qase_reporter_api_calls_total counter
+qase_reporter_api_calls_total 4
+# HELP qase_reporter_api_retries_total Number of Qase API calls retried after being rate limited.
+# TYPE qase_reporter_api_retries_total counter
+qase_reporter_api_retries_total 1
+`
+	require.Equal(t, expected, buf.String())
+}
+
+func TestWriteMetricsFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "metrics.prom")
+	require.Nil(t, writeMetricsFile(path, Metrics{Counts: ReportOutputCounts{Passed: 1, Total: 1}}))
+	content, err := os.ReadFile(path)
+	require.Nil(t, err)
+	require.Contains(t, string(content), "qase_reporter_results{status=\"passed\"} 1\n")
+
+	require.NotNil(t, writeMetricsFile(filepath.Join(t.TempDir(), "missing", "metrics.prom"), Metrics{}))
+}
+
+// failingWriter fails after accepting the given number of writes.
+type failingWriter struct {
+	writes int
+}
+
+func (f *failingWriter) Write(p []byte) (int, error) {
+	if f.writes == 0 {
+		return 0, errors.New("disk full")
+	}
+	f.writes--
+	return len(p), nil
+}
+
+func TestWriteMetricsError(t *testing.T) {
+	w := &failingWriter{writes: 3}
+	require.EqualError(t, writeMetrics(w, Metrics{}), "disk full")
+}
+
+func TestRunMetricsFileError(t *testing.T) {
+	originalConfig, originalReporter, originalStderr, originalCtx, originalCommentTemplate := config, reporter, stderr, ctx, commentTemplate
+	originalLogOutput := log.Writer()
+	defer func() {
+		config, reporter, stderr, ctx, commentTemplate = originalConfig, originalReporter, originalStderr, originalCtx, originalCommentTemplate
+		log.SetOutput(originalLogOutput)
+	}()
+	var logs strings.Builder
+	log.SetOutput(&logs)
+	stderr = io.Discard
+
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "report.jsonl")
+	line := `{"Action":"pass","Package":"example.com/foo","Test":"TestFoo_QASE-1","Elapsed":0.1}`
+	require.Nil(t, os.WriteFile(filename, []byte(line+"\n"), 0644))
+	config = Config{
+		Filenames:        []string{filename},
+		QaseApiToken:     "token",
+		QaseProject:      "DEMO",
+		SkipProjectCheck: true,
+		MetricsFile:      filepath.Join(dir, "missing", "metrics.prom"),
+	}
+	reporter = &fakeReporter{runId: 10, createResultBulkErr: errors.New("status code: 500")}
+
+	require.Equal(t, EXIT_CODE_REPORT_ERROR, run(cmd, nil))
+	reportErr := strings.Index(logs.String(), "Failed to report results: ")
+	metricsErr := strings.Index(logs.String(), "Failed to write metrics file: ")
+	require.NotEqual(t, -1, reportErr)
+	require.NotEqual(t, -1, metricsErr)
+	require.Less(t, reportErr, metricsErr)
+}
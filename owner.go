@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ownerRule assigns an owner to the packages starting with a prefix.
+type ownerRule struct {
+	PackagePrefix string
+	Owner         string
+}
+
+// parseOwnerMap parses the `package-prefix=team` entries of --owner-map.
+// The rules are sorted by descending prefix length so the most specific
+// prefix wins.
+func parseOwnerMap(entries []string) ([]ownerRule, error) {
+	rules := make([]ownerRule, 0, len(entries))
+	for _, entry := range entries {
+		prefix, owner, found := strings.Cut(entry, "=")
+		prefix = strings.TrimSpace(prefix)
+		owner = strings.TrimSpace(owner)
+		if !found || prefix == "" || owner == "" {
+			return nil, fmt.Errorf("invalid owner mapping %q, expected package-prefix=team", entry)
+		}
+		rules = append(rules, ownerRule{PackagePrefix: prefix, Owner: owner})
+	}
+	sort.SliceStable(rules, func(i, j int) bool {
+		return len(rules[i].PackagePrefix) > len(rules[j].PackagePrefix)
+	})
+	return rules, nil
+}
+
+// ownerOf returns the owner of the package, or empty if no rule matches.
+func ownerOf(pkg string, rules []ownerRule) string {
+	for _, rule := range rules {
+		if strings.HasPrefix(pkg, rule.PackagePrefix) {
+			return rule.Owner
+		}
+	}
+	return ""
+}
+
+// applyOwners sets the owner of each result by its package.
+func applyOwners(results []ReportResult, rules []ownerRule) []ReportResult {
+	if len(rules) == 0 {
+		return results
+	}
+	for i := range results {
+		results[i].Owner = ownerOf(results[i].Package, rules)
+	}
+	return results
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOwnerMap(t *testing.T) {
+	testcases := []struct {
+		name    string
+		entries []string
+		isError bool
+	}{
+		{name: "Valid", entries: []string{"github.com/org/repo/api=backend", "github.com/org/repo/web = frontend"}},
+		{name: "Missing separator", entries: []string{"github.com/org/repo"}, isError: true},
+		{name: "Empty team", entries: []string{"github.com/org/repo="}, isError: true},
+		{name: "Empty prefix", entries: []string{"=backend"}, isError: true},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := parseOwnerMap(tc.entries)
+			if tc.isError {
+				require.NotNil(t, err)
+			} else {
+				require.Nil(t, err)
+			}
+		})
+	}
+}
+
+func TestApplyOwners(t *testing.T) {
+	rules, err := parseOwnerMap([]string{
+		"github.com/org/repo=platform",
+		"github.com/org/repo/api=backend",
+		"github.com/org/repo/web=frontend",
+	})
+	require.Nil(t, err)
+
+	results := applyOwners([]ReportResult{
+		{Package: "github.com/org/repo/api/handlers", TestCaseId: 1},
+		{Package: "github.com/org/repo/web", TestCaseId: 2},
+		{Package: "github.com/org/repo/internal", TestCaseId: 3},
+		{Package: "github.com/other/lib", TestCaseId: 4},
+	}, rules)
+
+	owners := make([]string, 0)
+	for _, result := range results {
+		owners = append(owners, result.Owner)
+	}
+	require.Equal(t, []string{"backend", "frontend", "platform", ""}, owners)
+	require.Equal(t, "Package: github.com/org/repo/web\nOwner: frontend", createComment(results[1]))
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/petrabarus/go-qase-testing-reporter/pkg/parser"
+)
+
+const (
+	PARAMETERIZED_MODE_AGGREGATE = "aggregate"
+	PARAMETERIZED_MODE_MULTI     = "multi"
+)
+
+var defaultIdPatternRegexp = regexp.MustCompile(parser.DEFAULT_ID_PATTERN)
+
+// parameterOf returns the subtest path after the Qase ID of the test name,
+// e.g. "case_a" for "TestFoo_QASE-1/case_a". The test itself has no parameter.
+func parameterOf(test string) string {
+	end := lastQaseIdEnd(test)
+	if end < 0 {
+		return ""
+	}
+	rest := test[end:]
+	slash := strings.Index(rest, "/")
+	if slash < 0 {
+		return ""
+	}
+	return rest[slash+1:]
+}
+
+// lastQaseIdEnd is the end of the last Qase ID in the test name, of any of the
+// --id-pattern patterns, or -1 if there is none.
+func lastQaseIdEnd(test string) int {
+	patterns := idPatternRegexps
+	if len(patterns) == 0 {
+		patterns = []*regexp.Regexp{defaultIdPatternRegexp}
+	}
+	end := -1
+	for _, pattern := range patterns {
+		for _, loc := range pattern.FindAllStringIndex(test, -1) {
+			if loc[1] > end {
+				end = loc[1]
+			}
+		}
+	}
+	return end
+}
+
+// groupParameterizedResults handles a case run with multiple parameter sets,
+// i.e. subtests under the test with the Qase ID. The aggregate mode reports one
+// result with a breakdown per parameter set, the multi mode reports a result
+// per parameter set. In both modes, the parent test result is replaced by its
+// parameterized results.
+func groupParameterizedResults(results []ReportResult, mode string) ([]ReportResult, error) {
+	switch mode {
+	case "":
+		return results, nil
+	case PARAMETERIZED_MODE_AGGREGATE, PARAMETERIZED_MODE_MULTI:
+	default:
+		return nil, fmt.Errorf("unknown parameterized mode: %v", mode)
+	}
+
+	parameterized := make(map[int64][]ReportResult)
+	// The elapsed time of the parent tests, as the subtests may have none
+	parentTimeMs := make(map[int64]int64)
+	for i := range results {
+		results[i].Parameter = parameterOf(results[i].Test)
+		if results[i].Parameter != "" {
+			parameterized[results[i].TestCaseId] = append(parameterized[results[i].TestCaseId], results[i])
+		} else {
+			parentTimeMs[results[i].TestCaseId] = results[i].TimeMs
+		}
+	}
+
+	grouped := make([]ReportResult, 0, len(results))
+	aggregated := make(map[int64]bool)
+	for _, result := range results {
+		parameterSets, ok := parameterized[result.TestCaseId]
+		if !ok {
+			grouped = append(grouped, result)
+			continue
+		}
+		if mode == PARAMETERIZED_MODE_MULTI {
+			if result.Parameter != "" {
+				grouped = append(grouped, result)
+			}
+			continue
+		}
+		if aggregated[result.TestCaseId] {
+			continue
+		}
+		aggregated[result.TestCaseId] = true
+		aggregate := aggregateParameterSets(parameterSets)
+		if aggregate.TimeMs == 0 {
+			aggregate.TimeMs = parentTimeMs[result.TestCaseId]
+		}
+		grouped = append(grouped, aggregate)
+	}
+	return grouped, nil
+}
+
+func aggregateParameterSets(parameterSets []ReportResult) ReportResult {
+	result := parameterSets[0]
+	result.Parameter = ""
+	result.TimeMs = 0
+	lines := make([]string, 0, len(parameterSets))
+	for _, parameterSet := range parameterSets {
+		result.Status = mergeStatus(result.Status, parameterSet.Status)
+		result.TimeMs += parameterSet.TimeMs
+		lines = append(lines, fmt.Sprintf("%v: %v", parameterSet.Parameter, parameterSet.Status))
+	}
+	result.Comment = "Parameters:\n" + strings.Join(lines, "\n")
+	return result
+}
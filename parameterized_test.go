@@ -0,0 +1,111 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParameterOf(t *testing.T) {
+	require.Equal(t, "", parameterOf("TestFoo_QASE-1"))
+	require.Equal(t, "case_a", parameterOf("TestFoo_QASE-1/case_a"))
+	require.Equal(t, "case_a/nested", parameterOf("TestSuite/QASE-1/case_a/nested"))
+	require.Equal(t, "", parameterOf("TestFoo"))
+
+	defer func() { idPatternRegexps = nil }()
+	require.Nil(t, compilePatterns(Config{IdPatterns: []string{`QASE-(\d+)`, `TC-(\d+)`}}))
+	require.Equal(t, "case_a", parameterOf("TestFoo_TC-1/case_a"))
+	require.Equal(t, "case_a", parameterOf("TestFoo_QASE-1/TC-2/case_a"))
+
+	require.NotNil(t, compilePatterns(Config{IdPatterns: []string{`TC-\d+`}}))
+	require.NotNil(t, compilePatterns(Config{IdPatterns: []string{`TC-(\d+`}}))
+}
+
+func TestGroupParameterizedResults(t *testing.T) {
+	newResults := func() []ReportResult {
+		return []ReportResult{
+			{TestCaseId: 1, Test: "TestFoo_QASE-1/case_a", Status: TEST_CASE_RESULT_STATUS_PASSED, TimeMs: 10},
+			{TestCaseId: 1, Test: "TestFoo_QASE-1/case_b", Status: TEST_CASE_RESULT_STATUS_FAILED, TimeMs: 20},
+			{TestCaseId: 1, Test: "TestFoo_QASE-1", Status: TEST_CASE_RESULT_STATUS_FAILED, TimeMs: 30},
+			{TestCaseId: 2, Test: "TestBar_QASE-2", Status: TEST_CASE_RESULT_STATUS_PASSED, TimeMs: 5},
+		}
+	}
+
+	t.Run("Without mode keeps the results", func(t *testing.T) {
+		grouped, err := groupParameterizedResults(newResults(), "")
+		require.Nil(t, err)
+		require.Len(t, grouped, 4)
+	})
+
+	t.Run("Aggregate mode", func(t *testing.T) {
+		grouped, err := groupParameterizedResults(newResults(), PARAMETERIZED_MODE_AGGREGATE)
+		require.Nil(t, err)
+		require.Len(t, grouped, 2)
+		require.Equal(t, int64(1), grouped[0].TestCaseId)
+		require.Equal(t, TEST_CASE_RESULT_STATUS_FAILED, grouped[0].Status)
+		require.Equal(t, int64(30), grouped[0].TimeMs)
+		require.Equal(t, "Parameters:\ncase_a: passed\ncase_b: failed", grouped[0].Comment)
+		require.Equal(t, int64(2), grouped[1].TestCaseId)
+	})
+
+	t.Run("Aggregate mode with mixed statuses", func(t *testing.T) {
+		testcases := []struct {
+			statuses []string
+			expected string
+		}{
+			{[]string{TEST_CASE_RESULT_STATUS_BLOCKED, TEST_CASE_RESULT_STATUS_SKIPPED}, TEST_CASE_RESULT_STATUS_BLOCKED},
+			{[]string{TEST_CASE_RESULT_STATUS_PASSED, TEST_CASE_RESULT_STATUS_INVALID}, TEST_CASE_RESULT_STATUS_INVALID},
+			{[]string{TEST_CASE_RESULT_STATUS_IN_PROGRESS, TEST_CASE_RESULT_STATUS_SKIPPED}, TEST_CASE_RESULT_STATUS_SKIPPED},
+			{[]string{TEST_CASE_RESULT_STATUS_SKIPPED, TEST_CASE_RESULT_STATUS_SKIPPED}, TEST_CASE_RESULT_STATUS_SKIPPED},
+			{[]string{TEST_CASE_RESULT_STATUS_SKIPPED, TEST_CASE_RESULT_STATUS_PASSED}, TEST_CASE_RESULT_STATUS_PASSED},
+		}
+		for _, tc := range testcases {
+			results := []ReportResult{
+				{TestCaseId: 1, Test: "TestFoo_QASE-1/case_a", Status: tc.statuses[0]},
+				{TestCaseId: 1, Test: "TestFoo_QASE-1/case_b", Status: tc.statuses[1]},
+			}
+			grouped, err := groupParameterizedResults(results, PARAMETERIZED_MODE_AGGREGATE)
+			require.Nil(t, err)
+			require.Len(t, grouped, 1)
+			require.Equal(t, tc.expected, grouped[0].Status, "%v", tc.statuses)
+		}
+	})
+
+	t.Run("Multi mode", func(t *testing.T) {
+		grouped, err := groupParameterizedResults(newResults(), PARAMETERIZED_MODE_MULTI)
+		require.Nil(t, err)
+		require.Len(t, grouped, 3)
+		require.Equal(t, "case_a", grouped[0].Parameter)
+		require.Equal(t, TEST_CASE_RESULT_STATUS_PASSED, grouped[0].Status)
+		require.Equal(t, "case_b", grouped[1].Parameter)
+		require.Equal(t, TEST_CASE_RESULT_STATUS_FAILED, grouped[1].Status)
+		require.Equal(t, int64(2), grouped[2].TestCaseId)
+
+		qaseResults, _ := newResultCreates(nil, grouped)
+		require.Equal(t, map[string]string{"parameter": "case_a"}, qaseResults[0].Param)
+		require.Nil(t, qaseResults[2].Param)
+	})
+
+	t.Run("Aggregate mode falls back to the parent elapsed time", func(t *testing.T) {
+		input := strings.Join([]string{
+			`{"Action":"run","Package":"example.com/foo","Test":"TestFoo_QASE-1"}`,
+			`{"Action":"run","Package":"example.com/foo","Test":"TestFoo_QASE-1/case_a"}`,
+			`{"Action":"pass","Package":"example.com/foo","Test":"TestFoo_QASE-1/case_a","Elapsed":0}`,
+			`{"Action":"pass","Package":"example.com/foo","Test":"TestFoo_QASE-1","Elapsed":1.25}`,
+		}, "\n")
+		results, err := processReader(strings.NewReader(input))
+		require.Nil(t, err)
+
+		grouped, err := groupParameterizedResults(results, PARAMETERIZED_MODE_AGGREGATE)
+		require.Nil(t, err)
+		require.Len(t, grouped, 1)
+		require.Equal(t, int64(1250), grouped[0].TimeMs)
+		require.Equal(t, "Parameters:\ncase_a: passed", grouped[0].Comment)
+	})
+
+	t.Run("Unknown mode", func(t *testing.T) {
+		_, err := groupParameterizedResults(newResults(), "random")
+		require.NotNil(t, err)
+	})
+}
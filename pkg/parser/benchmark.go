@@ -0,0 +1,94 @@
+package parser
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// benchmarkRegexp matches the result line of a benchmark, e.g.
+// "BenchmarkFoo_QASE-900-8   1000000   1234 ns/op   16 B/op   1 allocs/op".
+// The GOMAXPROCS suffix (-8) is stripped by benchmarkName.
+var benchmarkRegexp = regexp.MustCompile(`^(Benchmark\S*)\s+(\d+)\s+([\d.]+) ns/op(?:\s+([\d.]+) B/op)?(?:\s+([\d.]+) allocs/op)?`)
+
+// parseBenchmarkLine creates a passed result from the output line of a
+// completed benchmark, since benchmarks do not have a clean pass action.
+// The ns/op and allocations are recorded in the comment.
+func (p *Parser) parseBenchmarkLine(line string) (result ReportResult, ok bool) {
+	if !strings.Contains(line, "ns/op") {
+		return
+	}
+	var content ReportJsonLine
+	if err := p.unmarshalLine(line, &content); err != nil {
+		return
+	}
+	if content.Action != "output" {
+		return
+	}
+	matches := benchmarkRegexp.FindStringSubmatch(strings.TrimSpace(content.Output))
+	if matches == nil {
+		return
+	}
+
+	name := p.benchmarkName(matches[1], content.Test)
+	qaseId, err := p.parseQaseIdByMatch(name)
+	if err != nil || qaseId == 0 {
+		return
+	}
+	iterations, err := strconv.ParseInt(matches[2], 10, 64)
+	if err != nil {
+		return
+	}
+	nsPerOp, err := strconv.ParseFloat(matches[3], 64)
+	if err != nil {
+		return
+	}
+
+	metrics := []string{fmt.Sprintf("%v ns/op", matches[3])}
+	if matches[4] != "" {
+		metrics = append(metrics, fmt.Sprintf("%v B/op", matches[4]))
+	}
+	if matches[5] != "" {
+		metrics = append(metrics, fmt.Sprintf("%v allocs/op", matches[5]))
+	}
+
+	result = ReportResult{
+		Package:    content.Package,
+		Test:       name,
+		TestCaseId: int64(qaseId),
+		Status:     TEST_CASE_RESULT_STATUS_PASSED,
+		TimeMs:     int64(float64(iterations) * nsPerOp / 1e6),
+		Comment:    fmt.Sprintf("Benchmark: %v iterations, %v", iterations, strings.Join(metrics, ", ")),
+	}
+	return result, true
+}
+
+// gomaxprocsSuffixRegexp matches the GOMAXPROCS suffix of a benchmark name.
+var gomaxprocsSuffixRegexp = regexp.MustCompile(`-\d+$`)
+
+// benchmarkName strips the GOMAXPROCS suffix from the name of the result line.
+// The suffix is left out when GOMAXPROCS is 1, so a trailing number that is
+// part of a Qase ID, e.g. of "BenchmarkFoo_QASE-900", is kept. The test of the
+// event is the name without the suffix, if set.
+func (p *Parser) benchmarkName(name string, test string) string {
+	if test != "" && (name == test || strings.HasPrefix(name, test+"-")) {
+		return test
+	}
+	suffix := gomaxprocsSuffixRegexp.FindStringIndex(name)
+	if suffix == nil {
+		return name
+	}
+	patterns := p.IdPatterns
+	if len(patterns) == 0 {
+		patterns = []*regexp.Regexp{qaseIdRegexp}
+	}
+	for _, pattern := range patterns {
+		for _, loc := range pattern.FindAllStringIndex(name, -1) {
+			if loc[0] <= suffix[0] && loc[1] == len(name) {
+				return name
+			}
+		}
+	}
+	return name[:suffix[0]]
+}
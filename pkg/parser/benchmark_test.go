@@ -0,0 +1,103 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseBenchmarkLine(t *testing.T) {
+	testcases := []struct {
+		name            string
+		line            string
+		expectedOk      bool
+		expectedId      int64
+		expectedTest    string
+		expectedTimeMs  int64
+		expectedComment string
+	}{
+		{
+			name:            "Benchmark with memory stats",
+			line:            `{"Action":"output","Package":"example.com/foo","Output":"BenchmarkFoo_QASE-900-8   \t 2000000\t       1500 ns/op\t      16 B/op\t       1 allocs/op\n"}`,
+			expectedOk:      true,
+			expectedId:      900,
+			expectedTest:    "BenchmarkFoo_QASE-900",
+			expectedTimeMs:  3000,
+			expectedComment: "Benchmark: 2000000 iterations, 1500 ns/op, 16 B/op, 1 allocs/op",
+		},
+		{
+			name:            "Benchmark without memory stats",
+			line:            `{"Action":"output","Package":"example.com/foo","Test":"BenchmarkBar_QASE-901","Output":"BenchmarkBar_QASE-901-8   \t 1000\t   1234.5 ns/op\n"}`,
+			expectedOk:      true,
+			expectedId:      901,
+			expectedTest:    "BenchmarkBar_QASE-901",
+			expectedTimeMs:  1,
+			expectedComment: "Benchmark: 1000 iterations, 1234.5 ns/op",
+		},
+		{
+			name:            "Benchmark with GOMAXPROCS 1",
+			line:            `{"Action":"output","Package":"example.com/foo","Output":"BenchmarkFoo_QASE-900   \t 1000\t   1234 ns/op\n"}`,
+			expectedOk:      true,
+			expectedId:      900,
+			expectedTest:    "BenchmarkFoo_QASE-900",
+			expectedTimeMs:  1,
+			expectedComment: "Benchmark: 1000 iterations, 1234 ns/op",
+		},
+		{
+			name:            "Sub-benchmark with GOMAXPROCS suffix",
+			line:            `{"Action":"output","Package":"example.com/foo","Output":"BenchmarkFoo_QASE-900/size-64-8   \t 1000\t   1234 ns/op\n"}`,
+			expectedOk:      true,
+			expectedId:      900,
+			expectedTest:    "BenchmarkFoo_QASE-900/size-64",
+			expectedTimeMs:  1,
+			expectedComment: "Benchmark: 1000 iterations, 1234 ns/op",
+		},
+		{
+			name:       "Benchmark without Qase ID",
+			line:       `{"Action":"output","Package":"example.com/foo","Output":"BenchmarkBaz-8   \t 1000\t   1234 ns/op\n"}`,
+			expectedOk: false,
+		},
+		{
+			name:       "Benchmark name line",
+			line:       `{"Action":"output","Package":"example.com/foo","Output":"BenchmarkFoo_QASE-900\n"}`,
+			expectedOk: false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, ok := (&Parser{}).parseBenchmarkLine(tc.line)
+			require.Equal(t, tc.expectedOk, ok)
+			if !tc.expectedOk {
+				return
+			}
+			require.Equal(t, tc.expectedId, result.TestCaseId)
+			require.Equal(t, tc.expectedTest, result.Test)
+			require.Equal(t, TEST_CASE_RESULT_STATUS_PASSED, result.Status)
+			require.Equal(t, tc.expectedTimeMs, result.TimeMs)
+			require.Equal(t, tc.expectedComment, result.Comment)
+		})
+	}
+}
+
+func TestParseReaderWithBenchmarks(t *testing.T) {
+	input := strings.Join([]string{
+		`{"Action":"start","Package":"example.com/foo"}`,
+		`{"Action":"output","Package":"example.com/foo","Output":"goos: linux\n"}`,
+		`{"Action":"output","Package":"example.com/foo","Output":"goarch: amd64\n"}`,
+		`{"Action":"output","Package":"example.com/foo","Output":"BenchmarkFoo_QASE-900\n"}`,
+		`{"Action":"output","Package":"example.com/foo","Output":"BenchmarkFoo_QASE-900-8   \t 2000000\t       1500 ns/op\t      16 B/op\t       1 allocs/op\n"}`,
+		`{"Action":"output","Package":"example.com/foo","Output":"BenchmarkBar_QASE-901-8   \t 1000\t   1234 ns/op\n"}`,
+		`{"Action":"output","Package":"example.com/foo","Output":"PASS\n"}`,
+		`{"Action":"output","Package":"example.com/foo","Output":"ok  \texample.com/foo\t3.000s\n"}`,
+		`{"Action":"pass","Package":"example.com/foo","Elapsed":3}`,
+	}, "\n")
+
+	results, err := (&Parser{}).ParseReader(strings.NewReader(input))
+	require.Nil(t, err)
+	require.Len(t, results, 2)
+	require.Equal(t, int64(900), results[0].TestCaseId)
+	require.Equal(t, int64(901), results[1].TestCaseId)
+	require.Equal(t, "Benchmark: 2000000 iterations, 1500 ns/op, 16 B/op, 1 allocs/op", results[0].Comment)
+}
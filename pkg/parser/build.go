@@ -0,0 +1,67 @@
+package parser
+
+import (
+	"strings"
+)
+
+// buildFailureTracker detects the packages that failed to build. Such a package
+// has no test events, only a package level fail, so its tests are not reported.
+type buildFailureTracker struct {
+	// outputs holds the build output of each package
+	outputs map[string]*strings.Builder
+	// failed marks the packages whose output says the build failed
+	failed map[string]bool
+}
+
+func newBuildFailureTracker() *buildFailureTracker {
+	return &buildFailureTracker{
+		outputs: make(map[string]*strings.Builder),
+		failed:  make(map[string]bool),
+	}
+}
+
+// observe follows the test2json events. On the package fail of a package that
+// failed to build it returns the package and its build output.
+func (b *buildFailureTracker) observe(content ReportJsonLine) (pkg string, output string, ok bool) {
+	switch {
+	case content.Action == "build-output":
+		// Since Go 1.24 the build output has its own events, keyed by the
+		// import path of the test binary, e.g. "example.com/foo [example.com/foo.test]"
+		importPath, _, _ := strings.Cut(content.ImportPath, " ")
+		b.write(importPath, content.Output)
+	case content.Action == "output" && content.Test == "":
+		if strings.Contains(content.Output, "[build failed]") || strings.Contains(content.Output, "[setup failed]") {
+			b.failed[content.Package] = true
+			b.write(content.Package, content.Output)
+		}
+	case content.Action == "fail" && content.Test == "":
+		if !b.failed[content.Package] && content.FailedBuild == "" {
+			return
+		}
+		output := ""
+		if builder, ok := b.outputs[content.Package]; ok {
+			output = builder.String()
+		}
+		return content.Package, output, true
+	}
+	return
+}
+
+func (b *buildFailureTracker) write(pkg string, output string) {
+	if b.outputs[pkg] == nil {
+		b.outputs[pkg] = &strings.Builder{}
+	}
+	b.outputs[pkg].WriteString(output)
+}
+
+// newBuildFailureResult reports the build failure of the package against
+// the --build-failure-case-id case.
+func newBuildFailureResult(pkg string, output string, caseId int64) ReportResult {
+	return ReportResult{
+		Package:    pkg,
+		TestCaseId: caseId,
+		Status:     TEST_CASE_RESULT_STATUS_FAILED,
+		Output:     output,
+		Comment:    "Build failed:\n" + output,
+	}
+}
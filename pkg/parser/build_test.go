@@ -0,0 +1,68 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseReaderBuildFailure(t *testing.T) {
+	testcases := []struct {
+		name  string
+		lines []string
+	}{
+		{
+			name: "Build failed output",
+			lines: []string{
+				`{"Action":"start","Package":"example.com/broken"}`,
+				`{"Action":"output","Package":"example.com/broken","Output":"FAIL\texample.com/broken [build failed]\n"}`,
+				`{"Action":"fail","Package":"example.com/broken","Elapsed":0}`,
+			},
+		},
+		{
+			name: "Build output events",
+			lines: []string{
+				`{"ImportPath":"example.com/broken [example.com/broken.test]","Action":"build-output","Output":"broken_test.go:3:1: syntax error\n"}`,
+				`{"ImportPath":"example.com/broken [example.com/broken.test]","Action":"build-fail"}`,
+				`{"Action":"start","Package":"example.com/broken"}`,
+				`{"Action":"output","Package":"example.com/broken","Output":"FAIL\texample.com/broken [build failed]\n"}`,
+				`{"Action":"fail","Package":"example.com/broken","Elapsed":0,"FailedBuild":"example.com/broken [example.com/broken.test]"}`,
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		input := strings.Join(append([]string{
+			`{"Action":"pass","Package":"example.com/foo","Test":"TestFoo_QASE-1","Elapsed":0.1}`,
+		}, tc.lines...), "\n")
+
+		t.Run(tc.name+" without case ID", func(t *testing.T) {
+			_, err := (&Parser{}).ParseReader(strings.NewReader(input))
+			require.ErrorIs(t, err, ErrBuildFailed)
+			require.Contains(t, err.Error(), "example.com/broken")
+		})
+
+		t.Run(tc.name+" with case ID", func(t *testing.T) {
+			results, err := (&Parser{BuildFailureCaseId: 99}).ParseReader(strings.NewReader(input))
+			require.Nil(t, err)
+			require.Len(t, results, 2)
+			require.Equal(t, int64(99), results[1].TestCaseId)
+			require.Equal(t, "example.com/broken", results[1].Package)
+			require.Equal(t, TEST_CASE_RESULT_STATUS_FAILED, results[1].Status)
+			require.Contains(t, results[1].Comment, "Build failed")
+		})
+	}
+}
+
+func TestParseReaderFailedPackageIsNotBuildFailure(t *testing.T) {
+	input := strings.Join([]string{
+		`{"Action":"fail","Package":"example.com/foo","Test":"TestFoo_QASE-1","Elapsed":0.1}`,
+		`{"Action":"output","Package":"example.com/foo","Output":"FAIL\texample.com/foo\t0.010s\n"}`,
+		`{"Action":"fail","Package":"example.com/foo","Elapsed":0.01}`,
+	}, "\n")
+
+	results, err := (&Parser{}).ParseReader(strings.NewReader(input))
+	require.Nil(t, err)
+	require.Len(t, results, 1)
+}
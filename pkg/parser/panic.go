@@ -0,0 +1,105 @@
+package parser
+
+import (
+	"strings"
+)
+
+// panicTracker attributes a panic to the tests that were running when it happened.
+// A panicking test binary may exit without a per-test fail action, leaving
+// only the panic output and the package fail, so the case would not be reported.
+type panicTracker struct {
+	parser *Parser
+	// running lists the tests of each package that have started but not finished.
+	running map[string][]string
+	// traces holds the panic output of each package, from the "panic:" line on.
+	traces map[string]*strings.Builder
+	// panicked marks the tests that were running at the time of the panic.
+	panicked map[string]bool
+}
+
+func newPanicTracker(parser *Parser) *panicTracker {
+	return &panicTracker{
+		parser:   parser,
+		running:  make(map[string][]string),
+		traces:   make(map[string]*strings.Builder),
+		panicked: make(map[string]bool),
+	}
+}
+
+// observe follows the test2json events. On the package fail after a panic it
+// returns a failed result for each running test with a Qase ID.
+func (p *panicTracker) observe(content ReportJsonLine) (results []ReportResult) {
+	switch {
+	case content.Action == "run" && content.Test != "":
+		p.running[content.Package] = append(p.running[content.Package], content.Test)
+	case content.Action == "output":
+		trace, ok := p.traces[content.Package]
+		if !ok && strings.HasPrefix(content.Output, "panic: ") {
+			trace = &strings.Builder{}
+			p.traces[content.Package] = trace
+			for _, test := range p.running[content.Package] {
+				p.panicked[testKey(content.Package, test)] = true
+			}
+			ok = true
+		}
+		if ok {
+			trace.WriteString(content.Output)
+		}
+	case content.Test != "" && !isNonTerminalAction(content.Action):
+		p.finish(content.Package, content.Test)
+	case content.Action == "fail":
+		for _, test := range p.running[content.Package] {
+			if !p.panicked[testKey(content.Package, test)] || !p.parser.matchesFilters(content.Package, test) {
+				continue
+			}
+			qaseId, err := p.parser.parseTestCaseId(ReportJsonLine{Package: content.Package, Test: test})
+			if err != nil || qaseId == 0 {
+				continue
+			}
+			results = append(results, ReportResult{
+				Package:    content.Package,
+				Test:       test,
+				TestCaseId: int64(qaseId),
+				Status:     TEST_CASE_RESULT_STATUS_FAILED,
+				Comment:    panicMessage(p.trace(content.Package)),
+				Stacktrace: p.trace(content.Package),
+			})
+		}
+		delete(p.running, content.Package)
+	}
+	return
+}
+
+// traceOf returns the panic trace for a failed result of a test that was
+// running when the package panicked.
+func (p *panicTracker) traceOf(result ReportResult) string {
+	if result.Status != TEST_CASE_RESULT_STATUS_FAILED || !p.panicked[testKey(result.Package, result.Test)] {
+		return ""
+	}
+	return p.trace(result.Package)
+}
+
+func (p *panicTracker) trace(pkg string) string {
+	trace, ok := p.traces[pkg]
+	if !ok {
+		return ""
+	}
+	return strings.TrimRight(trace.String(), "\n")
+}
+
+// panicMessage returns the first line of the panic trace, e.g. "panic: boom",
+// for the comment, while the full trace goes to the stack trace.
+func panicMessage(trace string) string {
+	message, _, _ := strings.Cut(trace, "\n")
+	return message
+}
+
+func (p *panicTracker) finish(pkg string, test string) {
+	running := p.running[pkg]
+	for i, runningTest := range running {
+		if runningTest == test {
+			p.running[pkg] = append(running[:i:i], running[i+1:]...)
+			return
+		}
+	}
+}
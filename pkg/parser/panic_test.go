@@ -0,0 +1,56 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseReaderWithPanic(t *testing.T) {
+	t.Run("Panic without a per-test fail action", func(t *testing.T) {
+		input := strings.Join([]string{
+			`{"Action":"start","Package":"example.com/foo"}`,
+			`{"Action":"run","Package":"example.com/foo","Test":"TestOk_QASE-1"}`,
+			`{"Action":"pass","Package":"example.com/foo","Test":"TestOk_QASE-1","Elapsed":0}`,
+			`{"Action":"run","Package":"example.com/foo","Test":"TestPanic_QASE-2"}`,
+			`{"Action":"output","Package":"example.com/foo","Test":"TestPanic_QASE-2","Output":"=== RUN   TestPanic_QASE-2\n"}`,
+			`{"Action":"output","Package":"example.com/foo","Output":"panic: boom\n"}`,
+			`{"Action":"output","Package":"example.com/foo","Output":"\n"}`,
+			`{"Action":"output","Package":"example.com/foo","Output":"goroutine 7 [running]:\n"}`,
+			`{"Action":"output","Package":"example.com/foo","Output":"example.com/foo.TestPanic_QASE-2.func1()\n"}`,
+			`{"Action":"output","Package":"example.com/foo","Output":"\t/src/foo/foo_test.go:12 +0x25\n"}`,
+			`{"Action":"output","Package":"example.com/foo","Output":"FAIL\texample.com/foo\t0.010s\n"}`,
+			`{"Action":"fail","Package":"example.com/foo","Elapsed":0.01}`,
+		}, "\n")
+
+		results, err := (&Parser{}).ParseReader(strings.NewReader(input))
+		require.Nil(t, err)
+		require.Len(t, results, 2)
+		require.Equal(t, int64(1), results[0].TestCaseId)
+		require.Equal(t, TEST_CASE_RESULT_STATUS_PASSED, results[0].Status)
+		require.Equal(t, int64(2), results[1].TestCaseId)
+		require.Equal(t, TEST_CASE_RESULT_STATUS_FAILED, results[1].Status)
+		require.Equal(t, "panic: boom", results[1].Comment)
+		require.True(t, strings.HasPrefix(results[1].Stacktrace, "panic: boom\n"))
+		require.Contains(t, results[1].Stacktrace, "foo_test.go:12")
+	})
+
+	t.Run("Panic with a per-test fail action", func(t *testing.T) {
+		input := strings.Join([]string{
+			`{"Action":"run","Package":"example.com/foo","Test":"TestPanic_QASE-2"}`,
+			`{"Action":"output","Package":"example.com/foo","Test":"TestPanic_QASE-2","Output":"--- FAIL: TestPanic_QASE-2 (0.00s)\n"}`,
+			`{"Action":"output","Package":"example.com/foo","Test":"TestPanic_QASE-2","Output":"panic: boom [recovered]\n"}`,
+			`{"Action":"output","Package":"example.com/foo","Test":"TestPanic_QASE-2","Output":"\tpanic: boom\n"}`,
+			`{"Action":"fail","Package":"example.com/foo","Test":"TestPanic_QASE-2","Elapsed":0}`,
+			`{"Action":"fail","Package":"example.com/foo","Elapsed":0.01}`,
+		}, "\n")
+
+		results, err := (&Parser{}).ParseReader(strings.NewReader(input))
+		require.Nil(t, err)
+		require.Len(t, results, 1)
+		require.Equal(t, TEST_CASE_RESULT_STATUS_FAILED, results[0].Status)
+		require.Equal(t, "panic: boom [recovered]", results[0].Comment)
+		require.Equal(t, "panic: boom [recovered]\n\tpanic: boom", results[0].Stacktrace)
+	})
+}
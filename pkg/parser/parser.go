@@ -0,0 +1,722 @@
+// Package parser parses the output of `go test -json` into the results of
+// Qase test cases. A test is linked to its case by a QASE-<id> in its name,
+// e.g. TestLogin_QASE-123.
+package parser
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	TEST_CASE_RESULT_STATUS_PASSED  = "passed"
+	TEST_CASE_RESULT_STATUS_FAILED  = "failed"
+	TEST_CASE_RESULT_STATUS_SKIPPED = "skipped"
+	TEST_CASE_RESULT_STATUS_BLOCKED = "blocked"
+	TEST_CASE_RESULT_STATUS_INVALID = "invalid"
+	// TEST_CASE_RESULT_STATUS_IN_PROGRESS is the status of a running test,
+	// see Parser.ReportInProgress.
+	TEST_CASE_RESULT_STATUS_IN_PROGRESS = "in_progress"
+)
+
+const (
+	// ID_MATCH_* pick the Qase ID of a test name with several
+	ID_MATCH_FIRST = "first"
+	ID_MATCH_LAST  = "last"
+	ID_MATCH_ALL   = "all"
+)
+
+// DEFAULT_ID_PATTERN finds the Qase IDs in the test names by default.
+const DEFAULT_ID_PATTERN = `QASE-(\d+)`
+
+// DEFAULT_MAX_LINE_BYTES is the size of the longest line read by default. The
+// test2json event of a test printing a large output can be well over the 64KB
+// of bufio.Scanner.
+const DEFAULT_MAX_LINE_BYTES = 16 * 1024 * 1024
+
+// ErrBuildFailed is returned by ParseReader when a package failed to build
+// and BuildFailureCaseId is not set.
+var ErrBuildFailed = errors.New("package failed to build")
+
+// ErrNoQaseId is returned by ParseLine for the result of a test without a
+// Qase ID.
+var ErrNoQaseId = errors.New("no Qase ID found in test name")
+
+var (
+	shuffleSeedRegexp = regexp.MustCompile(`^-test\.shuffle (\d+)`)
+	qaseIdRegexp      = regexp.MustCompile(DEFAULT_ID_PATTERN)
+)
+
+// ReportJsonLine is an event of `go test -json`, see `go doc test2json`. The
+// field names are matched case-insensitively, so the events written by other
+// tools, e.g. `gotestsum --jsonfile`, are read as well.
+type ReportJsonLine struct {
+	Time    string  `json:"time"`
+	Test    string  `json:"test"`   // The name of the test
+	Action  string  `json:"action"` // The action of the test, we shall check for "pass" or "fail"
+	Package string  `json:"package"`
+	Output  string  `json:"output"`
+	Elapsed float64 `json:"elapsed"`
+	// ImportPath is the package of a build-output event, since Go 1.24
+	ImportPath string `json:"importPath"`
+	// FailedBuild is set on the package fail when the build failed, since Go 1.24
+	FailedBuild string `json:"failedBuild"`
+}
+
+// ReportResult is the result of a test case.
+type ReportResult struct {
+	Package    string
+	Test       string
+	TestCaseId int64
+	Status     string
+	Time       time.Time
+	TimeMs     int64
+	// Parameter is the parameter set of a parameterized case, see --parameterized-mode
+	Parameter string
+	// Output is the output printed by the test
+	Output string
+	// Comment is appended to the result comment, e.g. the benchmark metrics.
+	Comment string
+	// ShuffleSeed is the seed of `go test -shuffle` for the package, if any.
+	ShuffleSeed string
+	// Owner is the team owning the package, see --owner-map
+	Owner string
+	// Severity is the severity of the case, see --severity-map
+	Severity string
+	// Stacktrace locates the failure, e.g. the panic trace or the file:line of t.Fatal
+	Stacktrace string
+}
+
+// Parser parses the test2json events. The zero value parses with the defaults.
+type Parser struct {
+	// IdMatch picks the Qase ID of a test name with several, one of ID_MATCH_*.
+	// The last one is picked by default.
+	IdMatch string
+	// IdPatterns find the Qase IDs in the test name, each with the ID as its
+	// first capture group, e.g. `TC-(\d+)` besides `QASE-(\d+)` during a
+	// migration. The IDs of all patterns are collected, in the order they
+	// appear in the name. DEFAULT_ID_PATTERN is used if empty.
+	IdPatterns []*regexp.Regexp
+	// PackageIdPattern finds the Qase ID in the package path of a test without
+	// one in its name. Its first capture group is the ID, e.g. `qase_(\d+)`.
+	PackageIdPattern *regexp.Regexp
+	// DirectivePattern finds the Qase ID in the output of a test, e.g. of a
+	// t.Log("qase:123"). Its first capture group is the ID. A directive takes
+	// precedence over the ID in the test name.
+	DirectivePattern *regexp.Regexp
+	// TestFilter keeps only the tests whose name matches, all tests if nil.
+	TestFilter *regexp.Regexp
+	// PackageFilter keeps only the tests of the packages whose path matches,
+	// all packages if nil. A test must match both filters.
+	PackageFilter *regexp.Regexp
+	// StatusMarkers map a marker in the test name to a status.
+	StatusMarkers []StatusMarker
+	// LenientJson parses a line that is not valid JSON from its first "{".
+	LenientJson bool
+	// BuildFailureCaseId is the case to report the packages that failed to
+	// build against. ParseReader fails with ErrBuildFailed if it is 0.
+	BuildFailureCaseId int64
+	// MaxLineBytes is the size of the longest line ParseReader reads,
+	// DEFAULT_MAX_LINE_BYTES if 0.
+	MaxLineBytes int
+	// RequireCaseId fails ParseReader when any test has no Qase ID, rather
+	// than skipping it. A parent test is not required to have one when its
+	// subtests have, e.g. TestSuite of TestSuite/QASE-1.
+	RequireCaseId bool
+	// ReportInProgress adds an in_progress result when a test starts to run,
+	// before its terminal result, which overwrites it in Qase.
+	ReportInProgress bool
+	// Strict fails ParseReader when any line is not valid JSON, rather than
+	// writing a warning.
+	Strict bool
+	// Warnings receives the warnings, e.g. about the lines that are not valid
+	// JSON. They are discarded if nil.
+	Warnings io.Writer
+	// Logf receives the debug messages. They are discarded if nil.
+	Logf func(format string, a ...any)
+
+	// validateDirectives are the directives of the lines given to ValidateLine
+	validateDirectives directiveTracker
+}
+
+func (p *Parser) warnings() io.Writer {
+	if p.Warnings == nil {
+		return io.Discard
+	}
+	return p.Warnings
+}
+
+func (p *Parser) logf(format string, a ...any) {
+	if p.Logf != nil {
+		p.Logf(format, a...)
+	}
+}
+
+// NewLineScanner returns a scanner of the lines of the reader, reading lines
+// up to maxLineBytes long, or DEFAULT_MAX_LINE_BYTES if it is 0.
+func NewLineScanner(reader io.Reader, maxLineBytes int) *bufio.Scanner {
+	if maxLineBytes <= 0 {
+		maxLineBytes = DEFAULT_MAX_LINE_BYTES
+	}
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), maxLineBytes)
+	return scanner
+}
+
+func (p *Parser) matchesPackageFilter(pkg string) bool {
+	return p.PackageFilter == nil || p.PackageFilter.MatchString(pkg)
+}
+
+func (p *Parser) matchesFilters(pkg string, test string) bool {
+	return p.matchesPackageFilter(pkg) && (p.TestFilter == nil || p.TestFilter.MatchString(test))
+}
+
+// ParseReader parses the output of `go test -json` into a result for each test
+// with a Qase ID. Besides the pass, fail and skip events, it reports the tests
+// running when their package panicked, benchmarks, and packages that failed to
+// build. The output of each test is kept in its result.
+func (p *Parser) ParseReader(reader io.Reader) (results []ReportResult, err error) {
+	scanner := NewLineScanner(reader, p.MaxLineBytes)
+
+	// Each package's test binary prints its own shuffle seed before running the tests.
+	shuffleSeeds := make(map[string]string)
+	// Interleaved parallel output may repeat the terminal event of a test.
+	lastTerminalKey := ""
+	panics := newPanicTracker(p)
+	buildFailures := newBuildFailureTracker()
+	// The output of each running test, keyed by package and test name since
+	// the output of parallel tests is interleaved. It is flushed when the
+	// terminal action of the test arrives.
+	outputs := make(map[string]*strings.Builder)
+	directives := make(directiveTracker)
+	// The final ok or FAIL line of each package
+	summaries := make(map[string]string)
+	// The status each test ended with, to reconcile with the summaries
+	tests := make(packageTests)
+	// The lines that are not JSON, e.g. from a truncated or corrupted file
+	parseErrors := make([]string, 0)
+	// The tests without a Qase ID, see RequireCaseId
+	missingIds := make([]string, 0)
+	results = make([]ReportResult, 0)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := scanner.Text()
+		if pkg, seed, ok := p.parseShuffleSeed(line); ok {
+			shuffleSeeds[pkg] = seed
+			continue
+		}
+		var content ReportJsonLine
+		testOutput := ""
+		directiveId := 0
+		if err := p.unmarshalLine(line, &content); err != nil {
+			if strings.TrimSpace(line) != "" {
+				parseErrors = append(parseErrors, fmt.Sprintf("line %d: %v", lineNumber, err))
+			}
+		} else {
+			panicResults := panics.observe(content)
+			results = append(results, panicResults...)
+			for _, result := range panicResults {
+				tests.record(result.Package, result.Test, result.Status)
+			}
+			if isTerminalAction(content.Action) && content.Test != "" && p.matchesFilters(content.Package, content.Test) {
+				tests.record(content.Package, content.Test, p.actionStatus(content))
+			}
+			if pkg, output, ok := buildFailures.observe(content); ok && p.matchesPackageFilter(pkg) {
+				if p.BuildFailureCaseId == 0 {
+					return nil, fmt.Errorf("%w: %v: %v", ErrBuildFailed, pkg, strings.TrimSpace(output))
+				}
+				results = append(results, newBuildFailureResult(pkg, output, p.BuildFailureCaseId))
+			}
+			if content.Action == "output" && content.Test != "" {
+				key := testKey(content.Package, content.Test)
+				if outputs[key] == nil {
+					outputs[key] = &strings.Builder{}
+				}
+				outputs[key].WriteString(content.Output)
+			}
+			directives.observe(p, content)
+			if isTerminalAction(content.Action) && content.Test != "" {
+				key := testKey(content.Package, content.Test)
+				if output, ok := outputs[key]; ok {
+					testOutput = output.String()
+					delete(outputs, key)
+				}
+			}
+			directiveId = directives.take(content)
+			if content.Action == "run" && testKey(content.Package, content.Test) == lastTerminalKey {
+				// Run again, e.g. with -count, so the next result is not a duplicate
+				lastTerminalKey = ""
+			}
+			if content.Action == "output" && content.Test == "" {
+				if pkg, status, ok := parsePackageSummary(content.Output); ok {
+					summaries[pkg] = status
+				}
+			}
+		}
+		result, ok := p.parseBenchmarkLine(line)
+		if ok {
+			tests.record(result.Package, result.Test, result.Status)
+		}
+		lineResults := []ReportResult{result}
+		if !ok {
+			var err error
+			lineResults, err = p.parseLineResults(line, directiveId)
+			if errors.Is(err, ErrNoQaseId) {
+				missingIds = append(missingIds, lineResults[0].Test)
+			}
+			if err != nil {
+				//log.Printf("Failed to process line: %v", err)
+				continue
+			}
+			result = lineResults[0]
+		}
+		if result.TestCaseId == 0 {
+			continue
+		}
+		terminalKey := testKey(result.Package, result.Test)
+		result.Output = testOutput
+		if trace := panics.traceOf(result); trace != "" {
+			result.Comment = panicMessage(trace)
+			result.Stacktrace = trace
+		} else if result.Status == TEST_CASE_RESULT_STATUS_FAILED {
+			result.Stacktrace = extractStacktrace(result.Output)
+		}
+		if result.Status != TEST_CASE_RESULT_STATUS_IN_PROGRESS {
+			if terminalKey == lastTerminalKey {
+				p.logf("Skipping duplicate %v event for test %v\n", result.Status, result.Test)
+				continue
+			}
+			lastTerminalKey = terminalKey
+		}
+		result.ShuffleSeed = shuffleSeeds[result.Package]
+		for _, lineResult := range lineResults {
+			result.TestCaseId = lineResult.TestCaseId
+			results = append(results, result)
+		}
+	}
+
+	if err = scanner.Err(); err != nil {
+		err = errors.Join(errors.New("failed to read file"), err)
+		return
+	}
+
+	if p.RequireCaseId {
+		if missing := testsWithoutId(missingIds, results); len(missing) > 0 {
+			return nil, fmt.Errorf("%d tests have no Qase ID: %v", len(missing), strings.Join(missing, ", "))
+		}
+	}
+
+	for _, warning := range reconcilePackageSummaries(summaries, tests, results) {
+		fmt.Fprintf(p.warnings(), "Warning: %v\n", warning)
+	}
+
+	if len(parseErrors) > 0 {
+		summary := summarizeParseErrors(parseErrors)
+		if p.Strict {
+			return nil, errors.New(summary)
+		}
+		fmt.Fprintf(p.warnings(), "Warning: %v\n", summary)
+	}
+
+	return
+}
+
+// MAX_PARSE_ERROR_EXAMPLES is the number of parse errors shown in the summary.
+const MAX_PARSE_ERROR_EXAMPLES = 3
+
+// testsWithoutId returns the tests without a Qase ID, once each, leaving out
+// the parents of the tests with a result.
+func testsWithoutId(missingIds []string, results []ReportResult) []string {
+	missing := make([]string, 0, len(missingIds))
+	seen := make(map[string]bool)
+	for _, test := range missingIds {
+		if seen[test] {
+			continue
+		}
+		seen[test] = true
+		isParent := false
+		for _, result := range results {
+			if strings.HasPrefix(result.Test, test+"/") {
+				isParent = true
+				break
+			}
+		}
+		if !isParent {
+			missing = append(missing, test)
+		}
+	}
+	return missing
+}
+
+// summarizeParseErrors counts the lines that failed to parse, with the first few as examples.
+func summarizeParseErrors(parseErrors []string) string {
+	examples := parseErrors
+	if len(examples) > MAX_PARSE_ERROR_EXAMPLES {
+		examples = examples[:MAX_PARSE_ERROR_EXAMPLES]
+	}
+	return fmt.Sprintf("%d lines failed to parse, e.g. %v", len(parseErrors), strings.Join(examples, "; "))
+}
+
+func testKey(pkg string, test string) string {
+	return pkg + "\x00" + test
+}
+
+// parseShuffleSeed extracts the seed printed by `go test -shuffle`, e.g.
+// "-test.shuffle 1716813236957066000", so the order can be reproduced.
+func (p *Parser) parseShuffleSeed(line string) (pkg string, seed string, ok bool) {
+	if !strings.Contains(line, "-test.shuffle") {
+		return
+	}
+	var content ReportJsonLine
+	if err := p.unmarshalLine(line, &content); err != nil {
+		return
+	}
+	if content.Action != "output" {
+		return
+	}
+	matches := shuffleSeedRegexp.FindStringSubmatch(content.Output)
+	if matches == nil {
+		return
+	}
+	return content.Package, matches[1], true
+}
+
+// unmarshalLine parses the test2json event. With LenientJson, a line that is
+// not valid JSON is parsed again from its first "{", ignoring anything after the
+// event, e.g. a log line interleaved with the JSON stream. The "\r" of a CRLF
+// line ending, e.g. of a file written on Windows, is trimmed.
+func (p *Parser) unmarshalLine(line string, content *ReportJsonLine) error {
+	line = strings.TrimSuffix(line, "\r")
+	err := json.Unmarshal([]byte(line), content)
+	if err == nil || !p.LenientJson {
+		return err
+	}
+	start := strings.Index(line, "{")
+	if start < 0 {
+		return err
+	}
+	*content = ReportJsonLine{}
+	if lenientErr := json.NewDecoder(strings.NewReader(line[start:])).Decode(content); lenientErr != nil {
+		return err
+	}
+	return nil
+}
+
+// ParseLineResults parses the line into a result for each Qase ID of the test
+// with ID_MATCH_ALL, or a single result otherwise.
+func (p *Parser) ParseLineResults(line string) (results []ReportResult, err error) {
+	return p.parseLineResults(line, 0)
+}
+
+// parseLineResults is ParseLineResults with the ID of a directive in the
+// output of the test, if any.
+func (p *Parser) parseLineResults(line string, directiveId int) (results []ReportResult, err error) {
+	result, err := p.parseLine(line, directiveId)
+	if err != nil || result.TestCaseId == 0 {
+		return []ReportResult{result}, err
+	}
+	if p.IdMatch != ID_MATCH_ALL || directiveId != 0 {
+		return []ReportResult{result}, nil
+	}
+	qaseIds, err := p.parseQaseIds(result.Test)
+	if err != nil || len(qaseIds) == 0 {
+		// The ID came from the package path
+		return []ReportResult{result}, err
+	}
+	for _, qaseId := range qaseIds {
+		result.TestCaseId = int64(qaseId)
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// ParseLine parses the line into the result of its test. The result is empty
+// without an error for the lines that have no result, e.g. a test that is
+// still running, or that is filtered out.
+func (p *Parser) ParseLine(line string) (result ReportResult, err error) {
+	return p.parseLine(line, 0)
+}
+
+// parseLine is ParseLine with the ID of a directive in the output of the test,
+// if any, which takes precedence over the ID in the test name.
+func (p *Parser) parseLine(line string, directiveId int) (result ReportResult, err error) {
+	var content ReportJsonLine
+	err = p.unmarshalLine(line, &content)
+	if err != nil {
+		err = errors.Join(errors.New("failed to parse line"), err)
+		return
+	}
+	if isNonTerminalAction(content.Action) && !(p.ReportInProgress && content.Action == "run") {
+		// Not an error, the result comes later with the terminal action
+		return
+	}
+	if content.Test == "" && content.Action == "output" {
+		// Package level output, e.g. the final "PASS" and "ok" lines
+		return
+	}
+	if content.Test == "" {
+		err = fmt.Errorf("no test name found in line: %v", line)
+		return
+	}
+	if !p.matchesFilters(content.Package, content.Test) {
+		// Not an error, the test is reported elsewhere
+		return
+	}
+
+	qaseId := directiveId
+	if qaseId == 0 {
+		qaseId, err = p.parseTestCaseId(content)
+		if err != nil {
+			err = errors.Join(fmt.Errorf("failed to parse Qase ID in line: %v", line), err)
+			return
+		}
+	}
+	if qaseId == 0 {
+		// The test is kept to tell which one has no ID
+		result.Test = content.Test
+		err = fmt.Errorf("%w: %v", ErrNoQaseId, content.Test)
+		return
+	}
+	result.TestCaseId = int64(qaseId)
+	result.Test = content.Test
+
+	if content.Action == "fail" {
+		result.Status = TEST_CASE_RESULT_STATUS_FAILED
+		// test failed
+	} else if content.Action == "pass" {
+		result.Status = TEST_CASE_RESULT_STATUS_PASSED
+		// test passed
+	} else if content.Action == "skip" {
+		result.Status = TEST_CASE_RESULT_STATUS_SKIPPED
+		// test skipped
+	} else if content.Action == "run" {
+		result.Status = TEST_CASE_RESULT_STATUS_IN_PROGRESS
+	} else {
+		err = fmt.Errorf("unknown action: %v", content.Action)
+		return
+	}
+	if status, ok := markedStatus(content.Test, p.StatusMarkers); ok && result.Status != TEST_CASE_RESULT_STATUS_IN_PROGRESS {
+		result.Status = status
+	}
+
+	if content.Time != "" {
+		result.Time, err = time.Parse(time.RFC3339, content.Time)
+		if err != nil {
+			err = errors.Join(fmt.Errorf("failed to parse time: %v", content.Time), err)
+			return
+		}
+		result.Time = result.Time.UTC()
+	}
+
+	if content.Elapsed != 0 {
+		// convert to ms
+		result.TimeMs = int64(content.Elapsed * 1000)
+	}
+
+	if content.Package != "" {
+		result.Package = content.Package
+	}
+
+	return
+}
+
+// directiveTracker keeps the Qase ID of the directive in the output of each
+// running test, keyed by package and test name, until the test ends.
+type directiveTracker map[string]int
+
+// observe records the directive of the output line of a test, if any.
+func (t directiveTracker) observe(p *Parser, content ReportJsonLine) {
+	if content.Action != "output" || content.Test == "" {
+		return
+	}
+	if qaseId, ok := p.parseDirective(content.Output); ok {
+		t[testKey(content.Package, content.Test)] = qaseId
+	}
+}
+
+// take returns the ID of the directive of the test ending with the event, or
+// 0 if there is none, and forgets it.
+func (t directiveTracker) take(content ReportJsonLine) int {
+	if !isTerminalAction(content.Action) || content.Test == "" {
+		return 0
+	}
+	key := testKey(content.Package, content.Test)
+	qaseId := t[key]
+	delete(t, key)
+	return qaseId
+}
+
+// parseDirective finds the Qase ID of a directive in the output line of a test.
+func (p *Parser) parseDirective(output string) (int, bool) {
+	if p.DirectivePattern == nil {
+		return 0, false
+	}
+	matches := p.DirectivePattern.FindStringSubmatch(output)
+	if len(matches) < 2 {
+		return 0, false
+	}
+	qaseId, err := strconv.Atoi(matches[1])
+	if err != nil || qaseId <= 0 {
+		return 0, false
+	}
+	return qaseId, true
+}
+
+// parseTestCaseId finds the Qase ID in the test name, or in the package path
+// when configured with PackageIdPattern.
+func (p *Parser) parseTestCaseId(content ReportJsonLine) (int, error) {
+	qaseId, err := p.parseQaseIdByMatch(content.Test)
+	if err != nil {
+		return 0, err
+	}
+	if qaseId == 0 && p.PackageIdPattern != nil {
+		qaseId, err = ParseQaseIdFromPackage(content.Package, p.PackageIdPattern)
+		if err != nil {
+			return 0, errors.Join(errors.New("failed to parse Qase ID from package"), err)
+		}
+	}
+	return qaseId, nil
+}
+
+// ValidateLine checks that the line is a valid test2json event and, if it is
+// the result of a test, that the test has a Qase ID. Like ParseReader, it
+// takes the ID of a directive in the output of the test, so the lines of an
+// input are to be validated in order with the same Parser.
+func (p *Parser) ValidateLine(line string) error {
+	var content ReportJsonLine
+	if err := p.unmarshalLine(line, &content); err != nil {
+		return fmt.Errorf("invalid JSON: %v", err)
+	}
+	if p.validateDirectives == nil {
+		p.validateDirectives = make(directiveTracker)
+	}
+	p.validateDirectives.observe(p, content)
+	directiveId := p.validateDirectives.take(content)
+	if content.Test == "" || !isTerminalAction(content.Action) || !p.matchesFilters(content.Package, content.Test) {
+		return nil
+	}
+	if directiveId != 0 {
+		// The directive takes precedence over the ID in the test name
+		return nil
+	}
+	qaseId, err := p.parseTestCaseId(content)
+	if err != nil {
+		return errors.Join(fmt.Errorf("invalid Qase ID in test %v", content.Test), err)
+	}
+	if qaseId == 0 {
+		return fmt.Errorf("no Qase ID found in test %v", content.Test)
+	}
+	return nil
+}
+
+// isNonTerminalAction tells whether the test2json action happens while the
+// test is still running, e.g. when parallel tests are paused and continued.
+func isNonTerminalAction(action string) bool {
+	switch action {
+	case "start", "run", "pause", "cont":
+		return true
+	}
+	return false
+}
+
+// isTerminalAction tells whether the action ends the test, i.e. it has a result.
+func isTerminalAction(action string) bool {
+	switch action {
+	case "pass", "fail", "skip":
+		return true
+	}
+	return false
+}
+
+// ParseQaseId returns the last Qase ID in the test name, e.g. 123 for
+// "TestLogin_QASE-123", or 0 if there is none.
+func ParseQaseId(test string) (int, error) {
+	qaseIds, err := ParseQaseIds(test)
+	if err != nil || len(qaseIds) == 0 {
+		return 0, err
+	}
+	return qaseIds[len(qaseIds)-1], nil
+}
+
+// ParseQaseIds returns all Qase IDs in the test name, in order.
+func ParseQaseIds(test string) ([]int, error) {
+	return ParseQaseIdsWithPatterns(test, nil)
+}
+
+// ParseQaseIdsWithPatterns returns the Qase IDs found by any of the patterns
+// in the test name, in the order they appear in it and once each. Each
+// pattern has the ID as its first capture group. DEFAULT_ID_PATTERN is used
+// if there are no patterns.
+func ParseQaseIdsWithPatterns(test string, patterns []*regexp.Regexp) ([]int, error) {
+	if len(patterns) == 0 {
+		patterns = []*regexp.Regexp{qaseIdRegexp}
+	}
+	type idMatch struct {
+		position int
+		qaseId   int
+	}
+	matches := make([]idMatch, 0)
+	for _, pattern := range patterns {
+		for _, loc := range pattern.FindAllStringSubmatchIndex(test, -1) {
+			if len(loc) < 4 || loc[2] < 0 {
+				continue
+			}
+			qaseId, err := strconv.Atoi(test[loc[2]:loc[3]])
+			if err != nil {
+				return nil, errors.New("failed to parse Qase ID")
+			}
+			matches = append(matches, idMatch{position: loc[2], qaseId: qaseId})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].position < matches[j].position
+	})
+	qaseIds := make([]int, 0, len(matches))
+	seen := make(map[int]bool)
+	for _, match := range matches {
+		if seen[match.qaseId] {
+			continue
+		}
+		seen[match.qaseId] = true
+		qaseIds = append(qaseIds, match.qaseId)
+	}
+	return qaseIds, nil
+}
+
+func (p *Parser) parseQaseIds(test string) ([]int, error) {
+	return ParseQaseIdsWithPatterns(test, p.IdPatterns)
+}
+
+// parseQaseIdByMatch picks the Qase ID of the test name per IdMatch. With
+// ID_MATCH_ALL it picks the last one, the others are added by ParseLineResults.
+func (p *Parser) parseQaseIdByMatch(test string) (int, error) {
+	qaseIds, err := p.parseQaseIds(test)
+	if err != nil || len(qaseIds) == 0 {
+		return 0, err
+	}
+	if p.IdMatch != ID_MATCH_FIRST {
+		return qaseIds[len(qaseIds)-1], nil
+	}
+	return qaseIds[0], nil
+}
+
+// ParseQaseIdFromPackage extracts the Qase ID from the package path using a
+// pattern whose first capture group is the ID, e.g. `qase_(\d+)`.
+func ParseQaseIdFromPackage(pkg string, pattern *regexp.Regexp) (int, error) {
+	matches := pattern.FindStringSubmatch(pkg)
+	if len(matches) < 2 {
+		return 0, nil
+	}
+	qaseId, err := strconv.Atoi(matches[1])
+	if err != nil {
+		return 0, errors.New("failed to parse Qase ID")
+	}
+	return qaseId, nil
+}
@@ -0,0 +1,558 @@
+package parser
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseQaseId(t *testing.T) {
+	testcases := []struct {
+		name     string
+		input    string
+		expected int
+	}{
+		{
+			name:     "QASE-1 Valid Qase ID",
+			input:    "QASE-123",
+			expected: 123,
+		},
+		{
+			name:     "QASE-3 Invalid Qase ID",
+			input:    "QASE-abc",
+			expected: 0,
+		},
+		{
+			name:     "QASE-4 Empty input",
+			input:    "",
+			expected: 0,
+		},
+		{
+			name:     "QASE-5 No Qase ID",
+			input:    "QASE-",
+			expected: 0,
+		},
+		{
+			name:     "QASE-6 Will choose the last Qase ID on multiple Qase ID - 1",
+			input:    "QASE-123/Halohalo_QASE-456",
+			expected: 456,
+		},
+		{
+			name:     "QASE-7 Will choose the last Qase ID on multiple Qase ID - 2",
+			input:    "QASE-123/Halohalo_QASE-456",
+			expected: 456,
+		},
+		{
+			name:     "QASE-8 Will choose the last Qase ID on multiple Qase ID - 3",
+			input:    "QASE-123/Halohalo_QASE-789",
+			expected: 789,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := ParseQaseId(tc.input)
+			require.Nil(t, err)
+			require.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
+func TestParseShuffleSeed(t *testing.T) {
+	testcases := []struct {
+		name         string
+		line         string
+		expectedPkg  string
+		expectedSeed string
+		expectedOk   bool
+	}{
+		{
+			name:         "Shuffle seed output",
+			line:         `{"Action":"output","Package":"example.com/foo","Output":"-test.shuffle 1716813236957066000\n"}`,
+			expectedPkg:  "example.com/foo",
+			expectedSeed: "1716813236957066000",
+			expectedOk:   true,
+		},
+		{
+			name:       "Regular output",
+			line:       `{"Action":"output","Package":"example.com/foo","Output":"PASS\n"}`,
+			expectedOk: false,
+		},
+		{
+			name:       "Invalid JSON",
+			line:       `-test.shuffle 1716813236957066000`,
+			expectedOk: false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			pkg, seed, ok := (&Parser{}).parseShuffleSeed(tc.line)
+			require.Equal(t, tc.expectedOk, ok)
+			require.Equal(t, tc.expectedPkg, pkg)
+			require.Equal(t, tc.expectedSeed, seed)
+		})
+	}
+}
+
+func TestParseQaseIds(t *testing.T) {
+	testcases := []struct {
+		name     string
+		input    string
+		expected []int
+	}{
+		{name: "No ID", input: "TestFoo", expected: []int{}},
+		{name: "One ID", input: "TestFoo_QASE-123", expected: []int{123}},
+		{name: "Two IDs", input: "TestFoo_QASE-123/QASE-456", expected: []int{123, 456}},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := ParseQaseIds(tc.input)
+			require.Nil(t, err)
+			require.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
+func TestParseQaseIdsWithPatterns(t *testing.T) {
+	patterns := []*regexp.Regexp{regexp.MustCompile(`QASE-(\d+)`), regexp.MustCompile(`TC-(\d+)`)}
+	testcases := []struct {
+		name     string
+		input    string
+		expected []int
+	}{
+		{name: "No ID", input: "TestFoo", expected: []int{}},
+		{name: "Legacy ID", input: "TestFoo_TC-12", expected: []int{12}},
+		{name: "Mixed IDs in name order", input: "TestFoo/TC-12/QASE-123", expected: []int{12, 123}},
+		{name: "Mixed IDs in name order, reversed", input: "TestFoo_QASE-123/TC-12", expected: []int{123, 12}},
+		{name: "Same ID in both formats", input: "TestFoo_QASE-12/TC-12", expected: []int{12}},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := ParseQaseIdsWithPatterns(tc.input, patterns)
+			require.Nil(t, err)
+			require.Equal(t, tc.expected, actual)
+		})
+	}
+
+	t.Run("Parser", func(t *testing.T) {
+		line := `{"Action":"pass","Package":"example.com/foo","Test":"TestFoo_QASE-123/TC-12/QASE-123"}`
+		testcases := []struct {
+			idMatch  string
+			expected []int64
+		}{
+			{idMatch: ID_MATCH_FIRST, expected: []int64{123}},
+			{idMatch: ID_MATCH_LAST, expected: []int64{12}},
+			{idMatch: ID_MATCH_ALL, expected: []int64{123, 12}},
+		}
+		for _, tc := range testcases {
+			parser := &Parser{IdMatch: tc.idMatch, IdPatterns: patterns}
+			results, err := parser.ParseLineResults(line)
+			require.Nil(t, err)
+			caseIds := make([]int64, 0, len(results))
+			for _, result := range results {
+				caseIds = append(caseIds, result.TestCaseId)
+			}
+			require.Equal(t, tc.expected, caseIds, tc.idMatch)
+		}
+	})
+}
+
+func TestParseQaseIdFromPackage(t *testing.T) {
+	pattern := regexp.MustCompile(`qase_(\d+)`)
+	testcases := []struct {
+		name     string
+		input    string
+		expected int
+	}{
+		{
+			name:     "ID in package path",
+			input:    "example.com/repo/qase_123/service",
+			expected: 123,
+		},
+		{
+			name:     "ID at the end of package path",
+			input:    "example.com/repo/qase_45",
+			expected: 45,
+		},
+		{
+			name:     "No ID in package path",
+			input:    "example.com/repo/service",
+			expected: 0,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := ParseQaseIdFromPackage(tc.input, pattern)
+			require.Nil(t, err)
+			require.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
+func TestParseReader(t *testing.T) {
+	input := strings.Join([]string{
+		`{"Time":"2024-05-27T12:00:00Z","Action":"run","Package":"example.com/foo","Test":"TestFoo_QASE-1"}`,
+		`{"Time":"2024-05-27T12:00:00Z","Action":"output","Package":"example.com/foo","Test":"TestFoo_QASE-1","Output":"    foo_test.go:12: expected 1, got 2\n"}`,
+		`{"Time":"2024-05-27T12:00:01Z","Action":"fail","Package":"example.com/foo","Test":"TestFoo_QASE-1","Elapsed":1.5}`,
+		`{"Time":"2024-05-27T12:00:02Z","Action":"pass","Package":"example.com/foo","Test":"TestBar_QASE-2/QASE-3","Elapsed":0.25}`,
+		`{"Time":"2024-05-27T12:00:02Z","Action":"skip","Package":"example.com/foo","Test":"TestBaz_QASE-4","Elapsed":0}`,
+		`{"Time":"2024-05-27T12:00:02Z","Action":"pass","Package":"example.com/foo","Test":"TestNoId","Elapsed":0}`,
+	}, "\n")
+
+	t.Run("Defaults", func(t *testing.T) {
+		results, err := (&Parser{}).ParseReader(strings.NewReader(input))
+		require.Nil(t, err)
+		require.Equal(t, []ReportResult{
+			{
+				Package:    "example.com/foo",
+				Test:       "TestFoo_QASE-1",
+				TestCaseId: 1,
+				Status:     TEST_CASE_RESULT_STATUS_FAILED,
+				Time:       time.Date(2024, 5, 27, 12, 0, 1, 0, time.UTC),
+				TimeMs:     1500,
+				Output:     "    foo_test.go:12: expected 1, got 2\n",
+				Stacktrace: "foo_test.go:12: expected 1, got 2",
+			},
+			{
+				Package:    "example.com/foo",
+				Test:       "TestBar_QASE-2/QASE-3",
+				TestCaseId: 3,
+				Status:     TEST_CASE_RESULT_STATUS_PASSED,
+				Time:       time.Date(2024, 5, 27, 12, 0, 2, 0, time.UTC),
+				TimeMs:     250,
+			},
+			{
+				Package:    "example.com/foo",
+				Test:       "TestBaz_QASE-4",
+				TestCaseId: 4,
+				Status:     TEST_CASE_RESULT_STATUS_SKIPPED,
+				Time:       time.Date(2024, 5, 27, 12, 0, 2, 0, time.UTC),
+			},
+		}, results)
+	})
+
+	t.Run("All IDs with a test filter", func(t *testing.T) {
+		parser := &Parser{IdMatch: ID_MATCH_ALL, TestFilter: regexp.MustCompile(`^TestBa`)}
+		results, err := parser.ParseReader(strings.NewReader(input))
+		require.Nil(t, err)
+		caseIds := make([]int64, 0, len(results))
+		for _, result := range results {
+			caseIds = append(caseIds, result.TestCaseId)
+		}
+		require.Equal(t, []int64{2, 3, 4}, caseIds)
+	})
+
+	t.Run("Strict", func(t *testing.T) {
+		parser := &Parser{Strict: true}
+		_, err := parser.ParseReader(strings.NewReader(input + "\n{\"Action\":"))
+		require.NotNil(t, err)
+	})
+
+	t.Run("Warnings", func(t *testing.T) {
+		var buf bytes.Buffer
+		parser := &Parser{Warnings: &buf}
+		_, err := parser.ParseReader(strings.NewReader(input + "\n{\"Action\":"))
+		require.Nil(t, err)
+		require.Contains(t, buf.String(), "Warning: 1 lines failed to parse")
+	})
+}
+
+func TestParseReaderPackageFilter(t *testing.T) {
+	input := strings.Join([]string{
+		`{"Action":"pass","Package":"example.com/shop/api","Test":"TestCheckout_QASE-1"}`,
+		`{"Action":"fail","Package":"example.com/shop/web","Test":"TestCheckout_QASE-2"}`,
+		`{"Action":"pass","Package":"example.com/shop/api","Test":"TestCatalog_QASE-3"}`,
+		`{"Action":"output","Package":"example.com/shop/web","Output":"FAIL\texample.com/shop/web [build failed]\n"}`,
+		`{"Action":"fail","Package":"example.com/shop/web"}`,
+	}, "\n")
+
+	testcases := []struct {
+		name          string
+		packageFilter string
+		testFilter    string
+		expected      []int64
+	}{
+		{
+			name:          "Package filter",
+			packageFilter: `/api$`,
+			expected:      []int64{1, 3},
+		},
+		{
+			name:          "Package and test filter",
+			packageFilter: `/api$`,
+			testFilter:    `^TestCheckout`,
+			expected:      []int64{1},
+		},
+		{
+			name:          "No package matches",
+			packageFilter: `/admin$`,
+			expected:      []int64{},
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			parser := &Parser{PackageFilter: regexp.MustCompile(tc.packageFilter)}
+			if tc.testFilter != "" {
+				parser.TestFilter = regexp.MustCompile(tc.testFilter)
+			}
+			results, err := parser.ParseReader(strings.NewReader(input))
+			require.Nil(t, err)
+			caseIds := make([]int64, 0, len(results))
+			for _, result := range results {
+				caseIds = append(caseIds, result.TestCaseId)
+			}
+			require.Equal(t, tc.expected, caseIds)
+		})
+	}
+}
+
+func TestParseReaderInProgress(t *testing.T) {
+	input := strings.Join([]string{
+		`{"Time":"2024-05-27T12:00:00Z","Action":"start","Package":"example.com/foo"}`,
+		`{"Time":"2024-05-27T12:00:00Z","Action":"run","Package":"example.com/foo","Test":"TestFoo_QASE-1"}`,
+		`{"Time":"2024-05-27T12:00:01Z","Action":"pass","Package":"example.com/foo","Test":"TestFoo_QASE-1","Elapsed":1}`,
+		`{"Time":"2024-05-27T12:00:01Z","Action":"run","Package":"example.com/foo","Test":"TestBar_QASE-2/@blocked"}`,
+		`{"Time":"2024-05-27T12:00:02Z","Action":"fail","Package":"example.com/foo","Test":"TestBar_QASE-2/@blocked","Elapsed":1}`,
+	}, "\n")
+	statusMarkers := []StatusMarker{{Marker: "@blocked", Status: TEST_CASE_RESULT_STATUS_BLOCKED}}
+
+	testcases := []struct {
+		name             string
+		reportInProgress bool
+		expected         []string
+	}{
+		{
+			name:     "Without the flag",
+			expected: []string{"1 passed", "2 blocked"},
+		},
+		{
+			name:             "With the flag",
+			reportInProgress: true,
+			expected:         []string{"1 in_progress", "1 passed", "2 in_progress", "2 blocked"},
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			parser := &Parser{ReportInProgress: tc.reportInProgress, StatusMarkers: statusMarkers}
+			results, err := parser.ParseReader(strings.NewReader(input))
+			require.Nil(t, err)
+			actual := make([]string, 0, len(results))
+			for _, result := range results {
+				actual = append(actual, fmt.Sprintf("%d %v", result.TestCaseId, result.Status))
+			}
+			require.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
+func TestParseReaderRerun(t *testing.T) {
+	// A test run twice with -count=2, failing then passing
+	input := strings.Join([]string{
+		`{"Action":"run","Package":"example.com/foo","Test":"TestFoo_QASE-1"}`,
+		`{"Action":"fail","Package":"example.com/foo","Test":"TestFoo_QASE-1","Elapsed":0.5}`,
+		`{"Action":"run","Package":"example.com/foo","Test":"TestFoo_QASE-1"}`,
+		`{"Action":"pass","Package":"example.com/foo","Test":"TestFoo_QASE-1","Elapsed":0.25}`,
+		`{"Action":"pass","Package":"example.com/foo","Test":"TestFoo_QASE-1","Elapsed":0.25}`,
+	}, "\n")
+	results, err := (&Parser{}).ParseReader(strings.NewReader(input))
+	require.Nil(t, err)
+	require.Len(t, results, 2)
+	require.Equal(t, TEST_CASE_RESULT_STATUS_FAILED, results[0].Status)
+	require.Equal(t, TEST_CASE_RESULT_STATUS_PASSED, results[1].Status)
+}
+
+func TestParseReaderGotestsum(t *testing.T) {
+	testcases := []struct {
+		name  string
+		input string
+	}{
+		{
+			// gotestsum --jsonfile writes the test2json events as they are
+			name: "gotestsum jsonfile",
+			input: strings.Join([]string{
+				`{"Time":"2024-05-27T19:00:00.123456+07:00","Action":"run","Package":"example.com/foo","Test":"TestFoo_QASE-1"}`,
+				`{"Time":"2024-05-27T19:00:00.234567+07:00","Action":"output","Package":"example.com/foo","Test":"TestFoo_QASE-1","Output":"    foo_test.go:12: expected 1, got 2\n"}`,
+				`{"Time":"2024-05-27T19:00:01.5+07:00","Action":"fail","Package":"example.com/foo","Test":"TestFoo_QASE-1","Elapsed":1.5}`,
+			}, "\n"),
+		},
+		{
+			name: "Lowercase fields",
+			input: strings.Join([]string{
+				`{"time":"2024-05-27T19:00:00.123456+07:00","action":"run","package":"example.com/foo","test":"TestFoo_QASE-1"}`,
+				`{"time":"2024-05-27T19:00:00.234567+07:00","action":"output","package":"example.com/foo","test":"TestFoo_QASE-1","output":"    foo_test.go:12: expected 1, got 2\n"}`,
+				`{"time":"2024-05-27T19:00:01.5+07:00","action":"fail","package":"example.com/foo","test":"TestFoo_QASE-1","elapsed":1.5}`,
+			}, "\n"),
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			var warnings bytes.Buffer
+			results, err := (&Parser{Warnings: &warnings}).ParseReader(strings.NewReader(tc.input))
+			require.Nil(t, err)
+			require.Empty(t, warnings.String())
+			require.Equal(t, []ReportResult{
+				{
+					Package:    "example.com/foo",
+					Test:       "TestFoo_QASE-1",
+					TestCaseId: 1,
+					Status:     TEST_CASE_RESULT_STATUS_FAILED,
+					Time:       time.Date(2024, 5, 27, 12, 0, 1, 500000000, time.UTC),
+					TimeMs:     1500,
+					Output:     "    foo_test.go:12: expected 1, got 2\n",
+					Stacktrace: "foo_test.go:12: expected 1, got 2",
+				},
+			}, results)
+		})
+	}
+}
+
+func TestParseReaderCRLF(t *testing.T) {
+	lines := []string{
+		`{"Time":"2024-05-27T12:00:00Z","Action":"run","Package":"example.com/foo","Test":"TestFoo_QASE-1"}`,
+		`{"Time":"2024-05-27T12:00:00Z","Action":"output","Package":"example.com/foo","Test":"TestFoo_QASE-1","Output":"    foo_test.go:12: expected 1, got 2\n"}`,
+		`{"Time":"2024-05-27T12:00:01Z","Action":"fail","Package":"example.com/foo","Test":"TestFoo_QASE-1","Elapsed":1.5}`,
+		`{"Time":"2024-05-27T12:00:02Z","Action":"pass","Package":"example.com/foo","Test":"TestBar_QASE-2","Elapsed":0.25}`,
+	}
+	var warnings bytes.Buffer
+	parser := &Parser{Warnings: &warnings}
+
+	lfResults, err := parser.ParseReader(strings.NewReader(strings.Join(lines, "\n") + "\n"))
+	require.Nil(t, err)
+	crlfResults, err := parser.ParseReader(strings.NewReader(strings.Join(lines, "\r\n") + "\r\n"))
+	require.Nil(t, err)
+	require.Len(t, crlfResults, 2)
+	require.Equal(t, lfResults, crlfResults)
+	require.Empty(t, warnings.String())
+
+	lfResult, err := parser.ParseLine(lines[2])
+	require.Nil(t, err)
+	crlfResult, err := parser.ParseLine(lines[2] + "\r")
+	require.Nil(t, err)
+	require.Equal(t, lfResult, crlfResult)
+}
+
+func TestParseReaderLongLine(t *testing.T) {
+	output := strings.Repeat("x", 100*1024)
+	input := `{"Action":"output","Package":"example.com/foo","Test":"TestFoo_QASE-1","Output":"` + output + `\n"}` + "\n" +
+		`{"Action":"pass","Package":"example.com/foo","Test":"TestFoo_QASE-1","Elapsed":0.1}`
+
+	results, err := (&Parser{}).ParseReader(strings.NewReader(input))
+	require.Nil(t, err)
+	require.Len(t, results, 1)
+	require.Equal(t, output+"\n", results[0].Output)
+
+	_, err = (&Parser{MaxLineBytes: 64 * 1024}).ParseReader(strings.NewReader(input))
+	require.ErrorIs(t, err, bufio.ErrTooLong)
+}
+
+func TestParseReaderRequireCaseId(t *testing.T) {
+	input := strings.Join([]string{
+		`{"Action":"pass","Package":"example.com/foo","Test":"TestFoo_QASE-1","Elapsed":0.1}`,
+		`{"Action":"pass","Package":"example.com/foo","Test":"TestHelper","Elapsed":0.1}`,
+		`{"Action":"pass","Package":"example.com/foo","Test":"TestSuite/QASE-2","Elapsed":0.1}`,
+		`{"Action":"pass","Package":"example.com/foo","Test":"TestSuite","Elapsed":0.1}`,
+		`{"Action":"pass","Package":"example.com/foo","Test":"TestHelper","Elapsed":0.1}`,
+	}, "\n")
+
+	testcases := []struct {
+		name          string
+		requireCaseId bool
+		err           string
+	}{
+		{name: "Not required", requireCaseId: false},
+		{name: "Required", requireCaseId: true, err: "1 tests have no Qase ID: TestHelper"},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			results, err := (&Parser{RequireCaseId: tc.requireCaseId}).ParseReader(strings.NewReader(input))
+			if tc.err != "" {
+				require.EqualError(t, err, tc.err)
+				return
+			}
+			require.Nil(t, err)
+			require.Len(t, results, 2)
+		})
+	}
+}
+
+func TestParseReaderDirective(t *testing.T) {
+	input := strings.Join([]string{
+		`{"Action":"run","Package":"example.com/foo","Test":"TestLogin"}`,
+		`{"Action":"output","Package":"example.com/foo","Test":"TestLogin","Output":"    login_test.go:10: //qase:123\n"}`,
+		`{"Action":"run","Package":"example.com/foo","Test":"TestLogout_QASE-1"}`,
+		`{"Action":"output","Package":"example.com/foo","Test":"TestLogout_QASE-1","Output":"    logout_test.go:10: qase:456\n"}`,
+		`{"Action":"pass","Package":"example.com/foo","Test":"TestLogin","Elapsed":0.1}`,
+		`{"Action":"output","Package":"example.com/foo","Test":"TestSignup_QASE-2/QASE-3","Output":"qase:789\n"}`,
+		`{"Action":"fail","Package":"example.com/foo","Test":"TestLogout_QASE-1","Elapsed":0.2}`,
+		`{"Action":"pass","Package":"example.com/foo","Test":"TestSignup_QASE-2/QASE-3","Elapsed":0.1}`,
+		`{"Action":"pass","Package":"example.com/foo","Test":"TestProfile_QASE-4","Elapsed":0.1}`,
+		`{"Action":"pass","Package":"example.com/foo","Test":"TestHelper","Elapsed":0.1}`,
+	}, "\n")
+
+	caseIds := func(results []ReportResult) []int64 {
+		ids := make([]int64, 0, len(results))
+		for _, result := range results {
+			ids = append(ids, result.TestCaseId)
+		}
+		return ids
+	}
+
+	t.Run("Directive wins over the test name", func(t *testing.T) {
+		parser := &Parser{IdMatch: ID_MATCH_ALL, DirectivePattern: regexp.MustCompile(`\bqase:(\d+)\b`)}
+		results, err := parser.ParseReader(strings.NewReader(input))
+		require.Nil(t, err)
+		require.Equal(t, []int64{123, 456, 789, 4}, caseIds(results))
+		require.Equal(t, "TestLogin", results[0].Test)
+		require.Equal(t, TEST_CASE_RESULT_STATUS_FAILED, results[1].Status)
+	})
+
+	t.Run("Without a directive pattern", func(t *testing.T) {
+		results, err := (&Parser{IdMatch: ID_MATCH_ALL}).ParseReader(strings.NewReader(input))
+		require.Nil(t, err)
+		require.Equal(t, []int64{1, 2, 3, 4}, caseIds(results))
+	})
+}
+
+func TestParseLine(t *testing.T) {
+	parser := &Parser{PackageIdPattern: regexp.MustCompile(`qase_(\d+)`)}
+
+	result, err := parser.ParseLine(`{"Action":"pass","Package":"example.com/qase_42","Test":"TestFoo","Elapsed":0.1}`)
+	require.Nil(t, err)
+	require.Equal(t, int64(42), result.TestCaseId)
+	require.Equal(t, TEST_CASE_RESULT_STATUS_PASSED, result.Status)
+	require.Equal(t, int64(100), result.TimeMs)
+
+	result, err = parser.ParseLine(`{"Action":"run","Package":"example.com/foo","Test":"TestFoo_QASE-1"}`)
+	require.Nil(t, err)
+	require.Equal(t, ReportResult{}, result)
+
+	_, err = parser.ParseLine(`{"Action":"pass","Package":"example.com/foo","Test":"TestFoo"}`)
+	require.EqualError(t, err, "no Qase ID found in test name: TestFoo")
+	require.ErrorIs(t, err, ErrNoQaseId)
+}
+
+func TestValidateLine(t *testing.T) {
+	parser := &Parser{}
+	require.Nil(t, parser.ValidateLine(`{"Action":"pass","Package":"example.com/foo","Test":"TestFoo_QASE-1"}`))
+	require.Nil(t, parser.ValidateLine(`{"Action":"output","Package":"example.com/foo","Output":"PASS\n"}`))
+	require.EqualError(t, parser.ValidateLine(`{"Action":"pass","Package":"example.com/foo","Test":"TestFoo"}`), "no Qase ID found in test TestFoo")
+	require.NotNil(t, parser.ValidateLine(`not json`))
+
+	t.Run("Directive", func(t *testing.T) {
+		parser := &Parser{DirectivePattern: regexp.MustCompile(`\bqase:(\d+)\b`)}
+		require.Nil(t, parser.ValidateLine(`{"Action":"output","Package":"example.com/foo","Test":"TestLogin","Output":"    login_test.go:10: qase:123\n"}`))
+		require.Nil(t, parser.ValidateLine(`{"Action":"pass","Package":"example.com/foo","Test":"TestLogin"}`))
+		// The directive is used once, by the test that printed it
+		require.EqualError(t, parser.ValidateLine(`{"Action":"pass","Package":"example.com/foo","Test":"TestLogin"}`), "no Qase ID found in test TestLogin")
+	})
+}
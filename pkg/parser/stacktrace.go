@@ -0,0 +1,22 @@
+package parser
+
+import (
+	"regexp"
+	"strings"
+)
+
+// stacktraceLineRegexp matches the lines with a file:line location, as printed
+// by t.Error and t.Fatal, by testify's "Error Trace", and in goroutine traces.
+var stacktraceLineRegexp = regexp.MustCompile(`\S+\.go:\d+`)
+
+// extractStacktrace returns the lines of the test output that locate the
+// failure, for the stacktrace of the result in Qase.
+func extractStacktrace(output string) string {
+	lines := make([]string, 0)
+	for _, line := range strings.Split(output, "\n") {
+		if stacktraceLineRegexp.MatchString(line) {
+			lines = append(lines, strings.TrimSpace(line))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,66 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractStacktrace(t *testing.T) {
+	testcases := []struct {
+		name     string
+		output   string
+		expected string
+	}{
+		{
+			name:     "t.Fatal",
+			output:   "=== RUN   TestFoo_QASE-1\n    foo_test.go:12: expected 1, got 2\n--- FAIL: TestFoo_QASE-1 (0.00s)\n",
+			expected: "foo_test.go:12: expected 1, got 2",
+		},
+		{
+			name: "testify",
+			output: "=== RUN   TestFoo_QASE-1\n" +
+				"    foo_test.go:20: \n" +
+				"        \tError Trace:\t/src/foo/foo_test.go:20\n" +
+				"        \tError:      \tNot equal\n" +
+				"--- FAIL: TestFoo_QASE-1 (0.00s)\n",
+			expected: "foo_test.go:20:\nError Trace:\t/src/foo/foo_test.go:20",
+		},
+		{
+			name:     "No location",
+			output:   "=== RUN   TestFoo_QASE-1\n--- FAIL: TestFoo_QASE-1 (0.00s)\n",
+			expected: "",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, extractStacktrace(tc.output))
+		})
+	}
+}
+
+func TestParseReaderStacktrace(t *testing.T) {
+	input := strings.Join([]string{
+		`{"Action":"run","Package":"example.com/foo","Test":"TestFail_QASE-1"}`,
+		`{"Action":"output","Package":"example.com/foo","Test":"TestFail_QASE-1","Output":"=== RUN   TestFail_QASE-1\n"}`,
+		`{"Action":"output","Package":"example.com/foo","Test":"TestFail_QASE-1","Output":"    foo_test.go:12: expected 1, got 2\n"}`,
+		`{"Action":"output","Package":"example.com/foo","Test":"TestFail_QASE-1","Output":"--- FAIL: TestFail_QASE-1 (0.00s)\n"}`,
+		`{"Action":"fail","Package":"example.com/foo","Test":"TestFail_QASE-1","Elapsed":0}`,
+		`{"Action":"run","Package":"example.com/foo","Test":"TestPass_QASE-2"}`,
+		`{"Action":"output","Package":"example.com/foo","Test":"TestPass_QASE-2","Output":"    foo_test.go:20: some log\n"}`,
+		`{"Action":"pass","Package":"example.com/foo","Test":"TestPass_QASE-2","Elapsed":0}`,
+		`{"Action":"run","Package":"example.com/foo","Test":"TestPanic_QASE-3"}`,
+		`{"Action":"output","Package":"example.com/foo","Output":"panic: boom\n"}`,
+		`{"Action":"output","Package":"example.com/foo","Output":"\t/src/foo/foo_test.go:30 +0x25\n"}`,
+		`{"Action":"fail","Package":"example.com/foo","Elapsed":0.01}`,
+	}, "\n")
+
+	results, err := (&Parser{}).ParseReader(strings.NewReader(input))
+	require.Nil(t, err)
+	require.Len(t, results, 3)
+	require.Equal(t, "foo_test.go:12: expected 1, got 2", results[0].Stacktrace)
+	require.Equal(t, "", results[1].Stacktrace)
+	require.Equal(t, "panic: boom\n\t/src/foo/foo_test.go:30 +0x25", results[2].Stacktrace)
+}
@@ -0,0 +1,53 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+)
+
+// StatusMarker maps a marker in the test name, e.g. "@blocked", to a Qase status.
+type StatusMarker struct {
+	Marker string
+	Status string
+}
+
+// IsQaseStatus tells whether the status is one of the result statuses of Qase.
+func IsQaseStatus(status string) bool {
+	switch status {
+	case TEST_CASE_RESULT_STATUS_PASSED,
+		TEST_CASE_RESULT_STATUS_FAILED,
+		TEST_CASE_RESULT_STATUS_SKIPPED,
+		TEST_CASE_RESULT_STATUS_BLOCKED,
+		TEST_CASE_RESULT_STATUS_INVALID:
+		return true
+	}
+	return false
+}
+
+// ParseStatusMap parses `marker=status` entries, e.g. "@blocked=blocked".
+func ParseStatusMap(entries []string) ([]StatusMarker, error) {
+	markers := make([]StatusMarker, 0, len(entries))
+	for _, entry := range entries {
+		marker, status, found := strings.Cut(entry, "=")
+		marker = strings.TrimSpace(marker)
+		status = strings.TrimSpace(status)
+		if !found || marker == "" || status == "" {
+			return nil, fmt.Errorf("invalid status mapping %q, expected marker=status", entry)
+		}
+		if !IsQaseStatus(status) {
+			return nil, fmt.Errorf("invalid status mapping %q, unknown status %q", entry, status)
+		}
+		markers = append(markers, StatusMarker{Marker: marker, Status: status})
+	}
+	return markers, nil
+}
+
+// markedStatus returns the status of the first marker found in the test name.
+func markedStatus(test string, markers []StatusMarker) (status string, ok bool) {
+	for _, marker := range markers {
+		if strings.Contains(test, marker.Marker) {
+			return marker.Status, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,67 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseStatusMap(t *testing.T) {
+	testcases := []struct {
+		name    string
+		entries []string
+		isError bool
+	}{
+		{name: "Valid", entries: []string{"@blocked=blocked", " @invalid = invalid "}},
+		{name: "Missing separator", entries: []string{"@blocked"}, isError: true},
+		{name: "Empty marker", entries: []string{"=blocked"}, isError: true},
+		{name: "Unknown status", entries: []string{"@broken=broken"}, isError: true},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ParseStatusMap(tc.entries)
+			if tc.isError {
+				require.NotNil(t, err)
+			} else {
+				require.Nil(t, err)
+			}
+		})
+	}
+}
+
+func TestParseLineStatusMap(t *testing.T) {
+	markers, err := ParseStatusMap([]string{"@blocked=blocked", "@invalid=invalid"})
+	require.Nil(t, err)
+	parser := &Parser{StatusMarkers: markers}
+
+	testcases := []struct {
+		name     string
+		line     string
+		expected string
+	}{
+		{
+			name:     "Blocked",
+			line:     `{"Action":"skip","Package":"example.com/foo","Test":"TestFoo_QASE-1/@blocked"}`,
+			expected: TEST_CASE_RESULT_STATUS_BLOCKED,
+		},
+		{
+			name:     "Invalid",
+			line:     `{"Action":"fail","Package":"example.com/foo","Test":"TestFoo_QASE-1/@invalid"}`,
+			expected: TEST_CASE_RESULT_STATUS_INVALID,
+		},
+		{
+			name:     "No marker",
+			line:     `{"Action":"pass","Package":"example.com/foo","Test":"TestFoo_QASE-1"}`,
+			expected: TEST_CASE_RESULT_STATUS_PASSED,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := parser.ParseLine(tc.line)
+			require.Nil(t, err)
+			require.Equal(t, tc.expected, result.Status)
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package parser
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+)
+
+// packageSummaryRegexp matches the final line go test prints for each package,
+// e.g. "ok  \texample.com/foo\t0.010s" or "FAIL\texample.com/foo\t0.010s".
+var packageSummaryRegexp = regexp.MustCompile(`^(ok|FAIL)\s+(\S+)`)
+
+// parsePackageSummary returns the package and its summary status, "ok" or "FAIL".
+func parsePackageSummary(output string) (pkg string, status string, ok bool) {
+	matches := packageSummaryRegexp.FindStringSubmatch(output)
+	if matches == nil {
+		return
+	}
+	return matches[2], matches[1], true
+}
+
+// reconcileStatuses are the statuses whose counts are reconciled, in the
+// order of the warnings.
+var reconcileStatuses = []string{
+	TEST_CASE_RESULT_STATUS_PASSED,
+	TEST_CASE_RESULT_STATUS_FAILED,
+	TEST_CASE_RESULT_STATUS_SKIPPED,
+	TEST_CASE_RESULT_STATUS_BLOCKED,
+	TEST_CASE_RESULT_STATUS_INVALID,
+}
+
+// packageTests is the status of each test of each package, keyed by package
+// and then test name.
+type packageTests map[string]map[string]string
+
+// record sets the status of the test, the last one wins as for the results.
+func (t packageTests) record(pkg string, test string, status string) {
+	if t[pkg] == nil {
+		t[pkg] = make(map[string]string)
+	}
+	t[pkg][test] = status
+}
+
+// count returns the number of tests of the package by status.
+func (t packageTests) count(pkg string) map[string]int {
+	counts := make(map[string]int)
+	for _, status := range t[pkg] {
+		counts[status]++
+	}
+	return counts
+}
+
+// reconcilePackageSummaries checks the package summaries against the parsed
+// results, e.g. a failed package without failed results means some failures
+// are not reported, and returns a warning for each mismatch. When the summary
+// agrees, the tests of the package that ended with each status are compared
+// with the tests that have a result with that status, to catch e.g. the
+// passed or skipped tests without a Qase ID.
+func reconcilePackageSummaries(summaries map[string]string, tests packageTests, results []ReportResult) []string {
+	failed := make(map[string]int)
+	reported := make(packageTests)
+	for _, result := range results {
+		if result.Status == TEST_CASE_RESULT_STATUS_FAILED {
+			failed[result.Package]++
+		}
+		if result.Test != "" && result.Status != TEST_CASE_RESULT_STATUS_IN_PROGRESS {
+			reported.record(result.Package, result.Test, result.Status)
+		}
+	}
+
+	packages := make([]string, 0, len(summaries))
+	for pkg := range summaries {
+		packages = append(packages, pkg)
+	}
+	sort.Strings(packages)
+
+	warnings := make([]string, 0)
+	for _, pkg := range packages {
+		switch {
+		case summaries[pkg] == "FAIL" && failed[pkg] == 0:
+			warnings = append(warnings, fmt.Sprintf("package %v failed but none of its results failed, the failing tests may have no Qase ID", pkg))
+		case summaries[pkg] == "ok" && failed[pkg] > 0:
+			warnings = append(warnings, fmt.Sprintf("package %v passed but %d of its results failed", pkg, failed[pkg]))
+		default:
+			expected, actual := tests.count(pkg), reported.count(pkg)
+			for _, status := range reconcileStatuses {
+				if expected[status] != actual[status] {
+					warnings = append(warnings, fmt.Sprintf("package %v has %d %v tests but %d %v results, the other tests may have no Qase ID",
+						pkg, expected[status], status, actual[status], status))
+				}
+			}
+		}
+	}
+	return warnings
+}
+
+// actionStatus returns the status of the terminal action of a test, with the
+// status of its marker if any, as the result of the test would have.
+func (p *Parser) actionStatus(content ReportJsonLine) string {
+	if status, ok := markedStatus(content.Test, p.StatusMarkers); ok {
+		return status
+	}
+	switch content.Action {
+	case "fail":
+		return TEST_CASE_RESULT_STATUS_FAILED
+	case "skip":
+		return TEST_CASE_RESULT_STATUS_SKIPPED
+	}
+	return TEST_CASE_RESULT_STATUS_PASSED
+}
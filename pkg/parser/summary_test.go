@@ -0,0 +1,109 @@
+package parser
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePackageSummary(t *testing.T) {
+	testcases := []struct {
+		name           string
+		output         string
+		expectedPkg    string
+		expectedStatus string
+		expectedOk     bool
+	}{
+		{name: "ok", output: "ok  \texample.com/foo\t0.010s\n", expectedPkg: "example.com/foo", expectedStatus: "ok", expectedOk: true},
+		{name: "ok cached", output: "ok  \texample.com/foo\t(cached)\n", expectedPkg: "example.com/foo", expectedStatus: "ok", expectedOk: true},
+		{name: "FAIL", output: "FAIL\texample.com/foo\t0.010s\n", expectedPkg: "example.com/foo", expectedStatus: "FAIL", expectedOk: true},
+		{name: "Bare FAIL", output: "FAIL\n"},
+		{name: "Other output", output: "coverage: 80.0% of statements\n"},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			pkg, status, ok := parsePackageSummary(tc.output)
+			require.Equal(t, tc.expectedOk, ok)
+			require.Equal(t, tc.expectedPkg, pkg)
+			require.Equal(t, tc.expectedStatus, status)
+		})
+	}
+}
+
+func TestParseReaderReconcilesPackageSummaries(t *testing.T) {
+	testcases := []struct {
+		name     string
+		lines    []string
+		expected string
+	}{
+		{
+			name: "Matching summaries",
+			lines: []string{
+				`{"Action":"pass","Package":"example.com/foo","Test":"TestFoo_QASE-1"}`,
+				`{"Action":"output","Package":"example.com/foo","Output":"ok  \texample.com/foo\t0.010s\n"}`,
+				`{"Action":"fail","Package":"example.com/bar","Test":"TestBar_QASE-2"}`,
+				`{"Action":"output","Package":"example.com/bar","Output":"FAIL\texample.com/bar\t0.010s\n"}`,
+			},
+			expected: "",
+		},
+		{
+			name: "Failed package without failed results",
+			lines: []string{
+				`{"Action":"pass","Package":"example.com/foo","Test":"TestFoo_QASE-1"}`,
+				`{"Action":"fail","Package":"example.com/foo","Test":"TestNoId"}`,
+				`{"Action":"output","Package":"example.com/foo","Output":"FAIL\texample.com/foo\t0.010s\n"}`,
+			},
+			expected: "Warning: package example.com/foo failed but none of its results failed, the failing tests may have no Qase ID\n",
+		},
+		{
+			name: "Passed package with failed results",
+			lines: []string{
+				`{"Action":"fail","Package":"example.com/foo","Test":"TestFoo_QASE-1"}`,
+				`{"Action":"output","Package":"example.com/foo","Output":"ok  \texample.com/foo\t0.010s\n"}`,
+			},
+			expected: "Warning: package example.com/foo passed but 1 of its results failed\n",
+		},
+		{
+			name: "Passed and skipped tests without results",
+			lines: []string{
+				`{"Action":"pass","Package":"example.com/foo","Test":"TestFoo_QASE-1"}`,
+				`{"Action":"pass","Package":"example.com/foo","Test":"TestNoId"}`,
+				`{"Action":"skip","Package":"example.com/foo","Test":"TestSkippedNoId"}`,
+				`{"Action":"output","Package":"example.com/foo","Output":"ok  \texample.com/foo\t0.010s\n"}`,
+			},
+			expected: "Warning: package example.com/foo has 2 passed tests but 1 passed results, the other tests may have no Qase ID\n" +
+				"Warning: package example.com/foo has 1 skipped tests but 0 skipped results, the other tests may have no Qase ID\n",
+		},
+		{
+			name: "Tests with several IDs and a rerun",
+			lines: []string{
+				`{"Action":"fail","Package":"example.com/foo","Test":"TestFoo_QASE-1/QASE-2"}`,
+				`{"Action":"run","Package":"example.com/foo","Test":"TestFoo_QASE-1/QASE-2"}`,
+				`{"Action":"fail","Package":"example.com/foo","Test":"TestFoo_QASE-1/QASE-2"}`,
+				`{"Action":"output","Package":"example.com/foo","Output":"FAIL\texample.com/foo\t0.010s\n"}`,
+			},
+			expected: "",
+		},
+		{
+			name: "Benchmark",
+			lines: []string{
+				`{"Action":"output","Package":"example.com/foo","Output":"BenchmarkFoo_QASE-900-8   \t 1000\t   1234 ns/op\n"}`,
+				`{"Action":"output","Package":"example.com/foo","Output":"ok  \texample.com/foo\t0.010s\n"}`,
+			},
+			expected: "",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			parser := &Parser{Warnings: &buf}
+			_, err := parser.ParseReader(strings.NewReader(strings.Join(tc.lines, "\n")))
+			require.Nil(t, err)
+			require.Equal(t, tc.expected, buf.String())
+		})
+	}
+}
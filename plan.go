@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// reconcilePlan checks the reported cases against the cases of the test plan
+// of the run, see --plan-id. A case not in the plan still gets its result, as
+// the results add their cases to the run, so it is only a warning.
+func reconcilePlan(reporter QaseReporter, planId int64, results []ReportResult) error {
+	planCaseIds, err := reporter.GetPlanCases(ctx, config.QaseProject, planId)
+	if err != nil {
+		return fmt.Errorf("%v, make sure plan %v exists", err, planId)
+	}
+	inPlan := make(map[int64]bool, len(planCaseIds))
+	for _, caseId := range planCaseIds {
+		inPlan[caseId] = true
+	}
+
+	reported := make(map[int64]bool, len(results))
+	notInPlan := make([]int64, 0)
+	for _, result := range results {
+		if reported[result.TestCaseId] {
+			continue
+		}
+		reported[result.TestCaseId] = true
+		if !inPlan[result.TestCaseId] {
+			notInPlan = append(notInPlan, result.TestCaseId)
+		}
+	}
+	if len(notInPlan) > 0 {
+		sort.Slice(notInPlan, func(i, j int) bool { return notInPlan[i] < notInPlan[j] })
+		fmt.Fprintf(stderr, "Warning: %d reported cases are not in plan %d: %v\n", len(notInPlan), planId, joinCaseIds(notInPlan))
+	}
+
+	untested := 0
+	for _, caseId := range planCaseIds {
+		if !reported[caseId] {
+			untested++
+		}
+	}
+	printVerbose("%d of %d cases of plan %d have no result\n", untested, len(planCaseIds), planId)
+	return nil
+}
+
+func joinCaseIds(caseIds []int64) string {
+	ids := make([]string, 0, len(caseIds))
+	for _, caseId := range caseIds {
+		ids = append(ids, fmt.Sprint(caseId))
+	}
+	return strings.Join(ids, ", ")
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunReportWithPlan(t *testing.T) {
+	originalConfig, originalStderr := config, stderr
+	defer func() { config, stderr = originalConfig, originalStderr }()
+	config.QaseProject = "DEMO"
+	config.QasePlanId = 7
+	var buf strings.Builder
+	stderr = &buf
+
+	results := []ReportResult{
+		{TestCaseId: 1, Status: TEST_CASE_RESULT_STATUS_PASSED},
+		{TestCaseId: 4, Status: TEST_CASE_RESULT_STATUS_FAILED},
+		{TestCaseId: 3, Status: TEST_CASE_RESULT_STATUS_PASSED},
+	}
+	reporter := &fakeReporter{runId: 10, planCases: []int64{1, 2}}
+	_, err := runReport(reporter, nil, results)
+	require.Nil(t, err)
+	require.Equal(t, []string{"GetPlanCases", "CreateRun", "CreateResultBulk", "CompleteRun"}, reporter.calls)
+	require.Equal(t, int64(7), reporter.runCreates[0].PlanId)
+	require.Empty(t, reporter.runCreates[0].Cases)
+	require.Len(t, reporter.resultBulks[0], 3)
+	require.Contains(t, buf.String(), "Warning: 2 reported cases are not in plan 7: 3, 4")
+
+	t.Run("Plan not found", func(t *testing.T) {
+		reporter := &fakeReporter{runId: 10, getPlanErr: errors.New("status code: 404")}
+		_, err := runReport(reporter, nil, results)
+		require.ErrorContains(t, err, "make sure plan 7 exists")
+		require.Equal(t, []string{"GetPlanCases"}, reporter.calls)
+	})
+
+	t.Run("Without a plan", func(t *testing.T) {
+		config.QasePlanId = 0
+		reporter := &fakeReporter{runId: 10}
+		_, err := runReport(reporter, nil, results)
+		require.Nil(t, err)
+		require.NotContains(t, reporter.calls, "GetPlanCases")
+		require.Zero(t, reporter.runCreates[0].PlanId)
+		require.Equal(t, []int64{1, 4, 3}, reporter.runCreates[0].Cases)
+	})
+}
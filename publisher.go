@@ -0,0 +1,96 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+)
+
+// Publisher publishes the report output for event-driven pipelines, see --publish-url.
+type Publisher interface {
+	Publish(ctx context.Context, output ReportOutput) error
+}
+
+// publisherFactories creates the publisher for each URL scheme. Queues like
+// AMQP or SQS plug in by registering their scheme here.
+var publisherFactories = map[string]func(u *url.URL) (Publisher, error){
+	"http":  newHttpPublisher,
+	"https": newHttpPublisher,
+	"file":  newFilePublisher,
+}
+
+func newPublisher(rawUrl string) (Publisher, error) {
+	u, err := url.Parse(rawUrl)
+	if err != nil {
+		return nil, fmt.Errorf("invalid publish URL %q: %v", maskUrl(rawUrl), urlParseError(err))
+	}
+	factory, ok := publisherFactories[u.Scheme]
+	if !ok {
+		return nil, fmt.Errorf("unsupported publish URL scheme: %q", u.Scheme)
+	}
+	return factory(u)
+}
+
+// httpPublisher posts the output as JSON, e.g. to a webhook or a queue's HTTP API.
+type httpPublisher struct {
+	url    string
+	client *http.Client
+}
+
+func newHttpPublisher(u *url.URL) (Publisher, error) {
+	return &httpPublisher{url: u.String(), client: http.DefaultClient}, nil
+}
+
+func (p *httpPublisher) Publish(ctx context.Context, output ReportOutput) error {
+	body, err := json.Marshal(output)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	httpResp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to publish output: %v", err)
+	}
+	defer httpResp.Body.Close()
+	if httpResp.StatusCode < 200 || httpResp.StatusCode >= 300 {
+		return fmt.Errorf("failed to publish output, status code: %v %s", httpResp.StatusCode, readBody(httpResp))
+	}
+	return nil
+}
+
+// filePublisher appends the output as a JSON line to a local file, e.g. a
+// spool directory watched by another process.
+type filePublisher struct {
+	path string
+}
+
+func newFilePublisher(u *url.URL) (Publisher, error) {
+	if u.Path == "" {
+		return nil, fmt.Errorf("publish URL has no file path: %v", u)
+	}
+	return &filePublisher{path: u.Path}, nil
+}
+
+func (p *filePublisher) Publish(ctx context.Context, output ReportOutput) error {
+	line, err := json.Marshal(output)
+	if err != nil {
+		return err
+	}
+	file, err := os.OpenFile(p.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to publish output: %v", err)
+	}
+	defer file.Close()
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to publish output: %v", err)
+	}
+	return file.Close()
+}
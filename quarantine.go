@@ -0,0 +1,57 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// readQuarantineFile reads the IDs of the cases known to fail, see
+// --quarantine-file, one per line. Blank lines and the lines starting with "#"
+// are skipped, and a QASE- prefix is allowed, e.g. "QASE-123  # flaky API".
+func readQuarantineFile(path string) (map[int64]bool, error) {
+	quarantined := make(map[int64]bool)
+	if path == "" {
+		return quarantined, nil
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		caseId, err := strconv.ParseInt(strings.TrimPrefix(line, "QASE-"), 10, 64)
+		if err != nil || caseId <= 0 {
+			return nil, fmt.Errorf("%v:%d: invalid case ID %q", path, lineNumber, line)
+		}
+		quarantined[caseId] = true
+	}
+	return quarantined, scanner.Err()
+}
+
+// unquarantinedFailures counts the failed cases that are not quarantined, which
+// fail the build with --exit-on-test-failure. The quarantined ones are listed.
+func unquarantinedFailures(output ReportOutput, quarantined map[int64]bool) (failed int, quarantinedFailures []int64) {
+	for _, testRun := range output.TestRuns {
+		if testRun.Status != TEST_CASE_RESULT_STATUS_FAILED {
+			continue
+		}
+		if quarantined[testRun.TestCaseId] {
+			quarantinedFailures = append(quarantinedFailures, testRun.TestCaseId)
+		} else {
+			failed++
+		}
+	}
+	sort.Slice(quarantinedFailures, func(i, j int) bool { return quarantinedFailures[i] < quarantinedFailures[j] })
+	return
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadQuarantineFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "quarantine.txt")
+	require.Nil(t, os.WriteFile(path, []byte("# Known to fail\n12\n\nQASE-34  # flaky API\n"), 0644))
+
+	quarantined, err := readQuarantineFile(path)
+	require.Nil(t, err)
+	require.Equal(t, map[int64]bool{12: true, 34: true}, quarantined)
+
+	quarantined, err = readQuarantineFile("")
+	require.Nil(t, err)
+	require.Empty(t, quarantined)
+
+	require.Nil(t, os.WriteFile(path, []byte("12\nTestFoo\n"), 0644))
+	_, err = readQuarantineFile(path)
+	require.ErrorContains(t, err, `quarantine.txt:2: invalid case ID "TestFoo"`)
+
+	_, err = readQuarantineFile(filepath.Join(dir, "missing.txt"))
+	require.NotNil(t, err)
+}
+
+func TestRunExitCodeQuarantine(t *testing.T) {
+	dir := t.TempDir()
+	quarantineFile := filepath.Join(dir, "quarantine.txt")
+	require.Nil(t, os.WriteFile(quarantineFile, []byte("1\n"), 0644))
+
+	failedQuarantined := `{"Action":"fail","Package":"example.com/foo","Test":"TestFoo_QASE-1","Elapsed":0.1}`
+	failed := `{"Action":"fail","Package":"example.com/foo","Test":"TestBar_QASE-2","Elapsed":0.1}`
+	passed := `{"Action":"pass","Package":"example.com/foo","Test":"TestBar_QASE-2","Elapsed":0.1}`
+	testcases := []struct {
+		name     string
+		lines    string
+		expected int
+	}{
+		{name: "Quarantined failure", lines: failedQuarantined + "\n" + passed + "\n", expected: EXIT_CODE_OK},
+		{name: "Quarantined and other failure", lines: failedQuarantined + "\n" + failed + "\n", expected: EXIT_CODE_TEST_FAILURE},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			originalConfig, originalReporter, originalStderr, originalCtx, originalCommentTemplate := config, reporter, stderr, ctx, commentTemplate
+			defer func() {
+				config, reporter, stderr, ctx, commentTemplate = originalConfig, originalReporter, originalStderr, originalCtx, originalCommentTemplate
+			}()
+			filename := filepath.Join(t.TempDir(), "report.jsonl")
+			require.Nil(t, os.WriteFile(filename, []byte(tc.lines), 0644))
+			config = Config{
+				Filenames:         []string{filename},
+				QaseApiToken:      "token",
+				QaseProject:       "DEMO",
+				SkipProjectCheck:  true,
+				ExitOnTestFailure: true,
+				QuarantineFile:    quarantineFile,
+			}
+			fake := &fakeReporter{runId: 10}
+			reporter = fake
+			stderr = io.Discard
+
+			require.Equal(t, tc.expected, run(cmd, nil))
+			// The quarantined failure is still reported as failed
+			require.Equal(t, int64(1), fake.resultBulks[0][0].CaseId)
+			require.Equal(t, TEST_CASE_RESULT_STATUS_FAILED, fake.resultBulks[0][0].Status)
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// QUEUE_FILE_VERSION is the version of the layout of the queue file, which is
+// read back by a later version of the command.
+const QUEUE_FILE_VERSION = 1
+
+// ReportQueue is the results that failed to be submitted, saved by
+// --queue-file to be submitted later with --resume. The results are saved as
+// they were to be submitted, after grouping, merging, and ordering, along with
+// the rendered run title and description.
+type ReportQueue struct {
+	Version        int            `json:"version"`
+	RunTitle       string         `json:"run_title"`
+	RunDescription string         `json:"run_description"`
+	Results        []ReportResult `json:"results"`
+}
+
+func newReportQueue(results []ReportResult) ReportQueue {
+	return ReportQueue{
+		Version:        QUEUE_FILE_VERSION,
+		RunTitle:       config.QaseRunTitle,
+		RunDescription: config.QaseRunDescription,
+		Results:        results,
+	}
+}
+
+// writeQueueFile saves the queue. It is only readable by the user, since the
+// results have the output of the tests.
+func writeQueueFile(path string, queue ReportQueue) error {
+	content, err := json.MarshalIndent(queue, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, content, 0o600)
+}
+
+func readQueueFile(path string) (queue ReportQueue, err error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return
+	}
+	err = json.Unmarshal(content, &queue)
+	if err != nil {
+		err = fmt.Errorf("invalid queue file %v: %v", path, err)
+		return
+	}
+	if queue.Version != QUEUE_FILE_VERSION {
+		err = fmt.Errorf("unsupported queue file version %v, expected %v", queue.Version, QUEUE_FILE_VERSION)
+	}
+	return
+}
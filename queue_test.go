@@ -0,0 +1,91 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueueFile(t *testing.T) {
+	originalConfig, originalReporter, originalStderr, originalCtx, originalCommentTemplate := config, reporter, stderr, ctx, commentTemplate
+	defer func() {
+		config, reporter, stderr, ctx, commentTemplate = originalConfig, originalReporter, originalStderr, originalCtx, originalCommentTemplate
+	}()
+	stderr = io.Discard
+
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "report.jsonl")
+	lines := `{"Action":"pass","Package":"example.com/foo","Test":"TestFoo_QASE-1","Elapsed":0.1}` + "\n" +
+		`{"Action":"fail","Package":"example.com/foo","Test":"TestBar_QASE-2","Elapsed":0.2}` + "\n"
+	require.Nil(t, os.WriteFile(filename, []byte(lines), 0644))
+	queueFile := filepath.Join(dir, "queue.json")
+	newConfig := func() Config {
+		return Config{
+			QaseApiToken:     "token",
+			QaseProject:      "DEMO",
+			QaseRunTitle:     "Nightly {{.Date}}",
+			SkipProjectCheck: true,
+			QueueFile:        queueFile,
+		}
+	}
+
+	t.Run("Saves the results on failure", func(t *testing.T) {
+		config = newConfig()
+		config.Filenames = []string{filename}
+		reporter = &fakeReporter{runId: 10, createResultBulkErr: errors.New("status code: 503")}
+		require.Equal(t, EXIT_CODE_REPORT_ERROR, run(cmd, nil))
+
+		queue, err := readQueueFile(queueFile)
+		require.Nil(t, err)
+		require.Equal(t, QUEUE_FILE_VERSION, queue.Version)
+		require.Regexp(t, `^Nightly \d{4}-\d{2}-\d{2}$`, queue.RunTitle)
+		require.Len(t, queue.Results, 2)
+		require.Equal(t, int64(1), queue.Results[0].TestCaseId)
+		require.Equal(t, TEST_CASE_RESULT_STATUS_FAILED, queue.Results[1].Status)
+		require.Equal(t, "TestBar_QASE-2", queue.Results[1].Test)
+	})
+
+	t.Run("Resumes from the saved results", func(t *testing.T) {
+		queue, err := readQueueFile(queueFile)
+		require.Nil(t, err)
+
+		config = newConfig()
+		config.Resume = queueFile
+		fake := &fakeReporter{runId: 11}
+		reporter = fake
+		require.Equal(t, EXIT_CODE_OK, run(cmd, nil))
+
+		require.Equal(t, []string{"CreateRun", "CreateResultBulk", "CompleteRun"}, fake.calls)
+		require.Equal(t, queue.RunTitle, fake.runCreates[0].Title)
+		require.Len(t, fake.resultBulks[0], 2)
+		require.Equal(t, int64(2), fake.resultBulks[0][1].CaseId)
+		require.Equal(t, TEST_CASE_RESULT_STATUS_FAILED, fake.resultBulks[0][1].Status)
+		require.NoFileExists(t, queueFile)
+	})
+
+	t.Run("Resume takes no files", func(t *testing.T) {
+		config = newConfig()
+		config.Resume = queueFile
+		config.Filenames = []string{filename}
+		require.ErrorContains(t, validateConfig(config), "--resume")
+	})
+}
+
+func TestReadQueueFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "queue.json")
+
+	require.Nil(t, os.WriteFile(path, []byte(`{"version":2,"results":[]}`), 0600))
+	_, err := readQueueFile(path)
+	require.ErrorContains(t, err, "unsupported queue file version 2")
+
+	require.Nil(t, os.WriteFile(path, []byte(`{"version":`), 0600))
+	_, err = readQueueFile(path)
+	require.ErrorContains(t, err, "invalid queue file")
+
+	_, err = readQueueFile(filepath.Join(t.TempDir(), "missing.json"))
+	require.NotNil(t, err)
+}
@@ -0,0 +1,388 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/antihax/optional"
+	qase "go.qase.io/client"
+)
+
+// QaseReporter is the part of the Qase API needed to report the test results.
+// It allows the reporting flow to be tested without calling the real API.
+type QaseReporter interface {
+	CreateRun(ctx context.Context, projectCode string, runCreate qase.RunCreate) (runId int32, err error)
+	CreateResultBulk(ctx context.Context, projectCode string, runId int32, results []qase.ResultCreate) error
+	CompleteRun(ctx context.Context, projectCode string, runId int32) error
+	ListRuns(ctx context.Context, projectCode string, search string, limit int32, offset int32) (runs []qase.Run, err error)
+	UploadAttachment(ctx context.Context, projectCode string, filename string, content []byte) (hash string, err error)
+	ListProjects(ctx context.Context, limit int32, offset int32) (projects []qase.Project, err error)
+	DeleteRun(ctx context.Context, projectCode string, runId int32) error
+	GetRun(ctx context.Context, projectCode string, runId int32) (run qase.Run, err error)
+	GetPlanCases(ctx context.Context, projectCode string, planId int64) (caseIds []int64, err error)
+	ListEnvironments(ctx context.Context, projectCode string, search string, limit int32) (environments []qase.Environment, err error)
+}
+
+// ErrRunAlreadyCompleted is returned by CompleteRun when the run was completed
+// before, e.g. by another shard reporting into the same run.
+var ErrRunAlreadyCompleted = errors.New("test run is already completed")
+
+// qaseApiReporter implements QaseReporter using the Qase API client.
+type qaseApiReporter struct {
+	client     *qase.APIClient
+	maxRetries int
+}
+
+func newQaseApiReporter(client *qase.APIClient, maxRetries int) *qaseApiReporter {
+	return &qaseApiReporter{client: client, maxRetries: maxRetries}
+}
+
+func (r *qaseApiReporter) CreateRun(ctx context.Context, projectCode string, runCreate qase.RunCreate) (runId int32, err error) {
+	var qaseResp qase.IdResponse
+	httpResp, err := withRetry(ctx, r.maxRetries, func() (httpResp *http.Response, err error) {
+		qaseResp, httpResp, err = r.client.RunsApi.CreateRun(ctx, runCreate, projectCode)
+		return
+	})
+	if err != nil {
+		err = fmt.Errorf("failed to create test run: %v %s", err, readBody(httpResp))
+		return
+	}
+
+	if httpResp.StatusCode != 200 {
+		err = fmt.Errorf("failed to create test run, status code: %v", httpResp.StatusCode)
+		return
+	}
+
+	runId = int32(qaseResp.Result.Id)
+	return
+}
+
+func (r *qaseApiReporter) CreateResultBulk(ctx context.Context, projectCode string, runId int32, results []qase.ResultCreate) (err error) {
+	var qaseResp qase.BaseResponse
+	httpResp, err := withRetry(ctx, r.maxRetries, func() (httpResp *http.Response, err error) {
+		qaseResp, httpResp, err = r.client.ResultsApi.CreateResultBulk(ctx, qase.ResultCreateBulk{
+			Results: results,
+		}, projectCode, runId)
+		return
+	})
+
+	if err != nil {
+		err = fmt.Errorf("failed to create test run results: %v %s", err, readBody(httpResp))
+		return
+	}
+
+	if httpResp.StatusCode != 200 {
+		err = fmt.Errorf("failed to create test run results, status code: %v %s", httpResp.StatusCode, readBody(httpResp))
+		return
+	}
+
+	if !qaseResp.Status {
+		err = fmt.Errorf("failed to create test run results, status false")
+		return
+	}
+
+	return
+}
+
+func (r *qaseApiReporter) CompleteRun(ctx context.Context, projectCode string, runId int32) (err error) {
+	var qaseResp qase.BaseResponse
+	httpResp, err := withRetry(ctx, r.maxRetries, func() (httpResp *http.Response, err error) {
+		qaseResp, httpResp, err = r.client.RunsApi.CompleteRun(ctx, projectCode, runId)
+		return
+	})
+	if err != nil {
+		message := fmt.Sprintf("%v %s%s", err, errorBody(err), readBody(httpResp))
+		if isRunAlreadyCompleted(message) {
+			return ErrRunAlreadyCompleted
+		}
+		err = fmt.Errorf("failed to complete test run: %v", message)
+		return
+	}
+
+	if httpResp.StatusCode != 200 {
+		body := readBody(httpResp)
+		if isRunAlreadyCompleted(string(body)) {
+			return ErrRunAlreadyCompleted
+		}
+		err = fmt.Errorf("failed to complete test run, status code: %v %s", httpResp.StatusCode, body)
+		return
+	}
+
+	if !qaseResp.Status {
+		err = fmt.Errorf("failed to complete test run, status false")
+		return
+	}
+
+	return nil
+}
+
+func (r *qaseApiReporter) ListRuns(ctx context.Context, projectCode string, search string, limit int32, offset int32) (runs []qase.Run, err error) {
+	var qaseResp qase.RunListResponse
+	httpResp, err := withRetry(ctx, r.maxRetries, func() (httpResp *http.Response, err error) {
+		qaseResp, httpResp, err = r.client.RunsApi.GetRuns(ctx, projectCode, &qase.RunsApiGetRunsOpts{
+			Search: optional.NewString(search),
+			Limit:  optional.NewInt32(limit),
+			Offset: optional.NewInt32(offset),
+		})
+		return
+	})
+	if err != nil {
+		err = fmt.Errorf("failed to list test runs: %v %s", err, readBody(httpResp))
+		return
+	}
+
+	if httpResp.StatusCode != 200 {
+		err = fmt.Errorf("failed to list test runs, status code: %v", httpResp.StatusCode)
+		return
+	}
+
+	if qaseResp.Result != nil {
+		runs = qaseResp.Result.Entities
+	}
+	return
+}
+
+func (r *qaseApiReporter) GetRun(ctx context.Context, projectCode string, runId int32) (run qase.Run, err error) {
+	var qaseResp qase.RunResponse
+	httpResp, err := withRetry(ctx, r.maxRetries, func() (httpResp *http.Response, err error) {
+		qaseResp, httpResp, err = r.client.RunsApi.GetRun(ctx, projectCode, runId, nil)
+		return
+	})
+	if err != nil {
+		err = fmt.Errorf("failed to get test run: %v %s", err, readBody(httpResp))
+		return
+	}
+
+	if httpResp.StatusCode != 200 {
+		err = fmt.Errorf("failed to get test run, status code: %v", httpResp.StatusCode)
+		return
+	}
+
+	if qaseResp.Result != nil {
+		run = *qaseResp.Result
+	}
+	return
+}
+
+func (r *qaseApiReporter) GetPlanCases(ctx context.Context, projectCode string, planId int64) (caseIds []int64, err error) {
+	var qaseResp qase.PlanResponse
+	httpResp, err := withRetry(ctx, r.maxRetries, func() (httpResp *http.Response, err error) {
+		qaseResp, httpResp, err = r.client.PlansApi.GetPlan(ctx, projectCode, int32(planId))
+		return
+	})
+	if err != nil {
+		err = fmt.Errorf("failed to get test plan: %v %s", err, readBody(httpResp))
+		return
+	}
+
+	if httpResp.StatusCode != 200 {
+		err = fmt.Errorf("failed to get test plan, status code: %v", httpResp.StatusCode)
+		return
+	}
+
+	if qaseResp.Result != nil {
+		for _, planCase := range qaseResp.Result.Cases {
+			caseIds = append(caseIds, planCase.CaseId)
+		}
+	}
+	return
+}
+
+func (r *qaseApiReporter) ListProjects(ctx context.Context, limit int32, offset int32) (projects []qase.Project, err error) {
+	var qaseResp qase.ProjectListResponse
+	httpResp, err := withRetry(ctx, r.maxRetries, func() (httpResp *http.Response, err error) {
+		qaseResp, httpResp, err = r.client.ProjectsApi.GetProjects(ctx, &qase.ProjectsApiGetProjectsOpts{
+			Limit:  optional.NewInt32(limit),
+			Offset: optional.NewInt32(offset),
+		})
+		return
+	})
+	if err != nil {
+		err = fmt.Errorf("failed to list projects: %v %s", err, readBody(httpResp))
+		return
+	}
+
+	if httpResp.StatusCode != 200 {
+		err = fmt.Errorf("failed to list projects, status code: %v", httpResp.StatusCode)
+		return
+	}
+
+	if qaseResp.Result != nil {
+		projects = qaseResp.Result.Entities
+	}
+	return
+}
+
+func (r *qaseApiReporter) ListEnvironments(ctx context.Context, projectCode string, search string, limit int32) (environments []qase.Environment, err error) {
+	var qaseResp qase.EnvironmentListResponse
+	httpResp, err := withRetry(ctx, r.maxRetries, func() (httpResp *http.Response, err error) {
+		qaseResp, httpResp, err = r.client.EnvironmentsApi.GetEnvironments(ctx, projectCode, &qase.EnvironmentsApiGetEnvironmentsOpts{
+			Search: optional.NewString(search),
+			Limit:  optional.NewInt32(limit),
+		})
+		return
+	})
+	if err != nil {
+		err = fmt.Errorf("failed to get environments: %v %s", err, readBody(httpResp))
+		return
+	}
+
+	if httpResp.StatusCode != 200 {
+		err = fmt.Errorf("failed to get environments, status code: %v", httpResp.StatusCode)
+		return
+	}
+
+	if qaseResp.Result != nil {
+		environments = qaseResp.Result.Entities
+	}
+	return
+}
+
+func (r *qaseApiReporter) DeleteRun(ctx context.Context, projectCode string, runId int32) (err error) {
+	httpResp, err := withRetry(ctx, r.maxRetries, func() (httpResp *http.Response, err error) {
+		_, httpResp, err = r.client.RunsApi.DeleteRun(ctx, projectCode, runId)
+		return
+	})
+	if err != nil {
+		err = fmt.Errorf("failed to delete test run: %v %s", err, readBody(httpResp))
+		return
+	}
+
+	if httpResp.StatusCode != 200 {
+		err = fmt.Errorf("failed to delete test run, status code: %v", httpResp.StatusCode)
+		return
+	}
+	return nil
+}
+
+func (r *qaseApiReporter) UploadAttachment(ctx context.Context, projectCode string, filename string, content []byte) (hash string, err error) {
+	// The API client uploads files from the disk
+	dir, err := os.MkdirTemp("", "go-qase-testing-reporter")
+	if err != nil {
+		return
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, filename)
+	err = os.WriteFile(path, content, 0o600)
+	if err != nil {
+		return
+	}
+
+	var qaseResp qase.AttachmentUploadsResponse
+	httpResp, err := withRetry(ctx, r.maxRetries, func() (httpResp *http.Response, err error) {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		qaseResp, httpResp, err = r.client.AttachmentsApi.UploadAttachment(ctx, projectCode, &qase.AttachmentsApiUploadAttachmentOpts{
+			File: optional.NewInterface(file),
+		})
+		return
+	})
+	if err != nil {
+		err = fmt.Errorf("failed to upload attachment: %v %s", err, readBody(httpResp))
+		return
+	}
+
+	if httpResp.StatusCode != 200 {
+		err = fmt.Errorf("failed to upload attachment, status code: %v", httpResp.StatusCode)
+		return
+	}
+
+	if len(qaseResp.Result) == 0 {
+		err = fmt.Errorf("failed to upload attachment, no attachment returned")
+		return
+	}
+	hash = qaseResp.Result[0].Hash
+	return
+}
+
+// newHttpClient creates the HTTP client for the Qase API. The proxy is taken
+// from the HTTP_PROXY, HTTPS_PROXY, and NO_PROXY environment variables unless
+// an explicit proxy URL is given.
+func newHttpClient(proxy string) (*http.Client, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = http.ProxyFromEnvironment
+	if proxy != "" {
+		proxyUrl, err := url.Parse(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse proxy URL %v: %v", maskUrl(proxy), urlParseError(err))
+		}
+		if proxyUrl.Scheme == "" || proxyUrl.Host == "" {
+			return nil, fmt.Errorf("proxy URL must have a scheme and a host: %v", maskUrl(proxy))
+		}
+		transport.Proxy = http.ProxyURL(proxyUrl)
+	}
+	return &http.Client{Transport: transport}, nil
+}
+
+// headerNameRegexp matches the token of an HTTP header name, see RFC 9110.
+var headerNameRegexp = regexp.MustCompile("^[!#$%&'*+\\-.^_`|~0-9A-Za-z]+$")
+
+// parseHeaders parses the `Name: Value` entries of --header. The token is set
+// with --api-token, so it cannot be overridden here.
+func parseHeaders(entries []string) (map[string]string, error) {
+	headers := make(map[string]string, len(entries))
+	for _, entry := range entries {
+		name, value, found := strings.Cut(entry, ":")
+		name = strings.TrimSpace(name)
+		value = strings.TrimSpace(value)
+		if !found || !headerNameRegexp.MatchString(name) {
+			return nil, fmt.Errorf("invalid header %q, expected Name: Value", entry)
+		}
+		if strings.ContainsAny(value, "\r\n") {
+			return nil, fmt.Errorf("invalid header %q, the value has a line break", entry)
+		}
+		if http.CanonicalHeaderKey(name) == "Token" {
+			return nil, fmt.Errorf("invalid header %q, use --api-token to set the token", entry)
+		}
+		headers[name] = value
+	}
+	return headers, nil
+}
+
+// newQaseConfiguration configures the Qase API client with the token and the
+// extra headers.
+func newQaseConfiguration(httpClient *http.Client, token string, headers map[string]string) *qase.Configuration {
+	configuration := qase.NewConfiguration()
+	configuration.HTTPClient = httpClient
+	for name, value := range headers {
+		configuration.AddDefaultHeader(name, value)
+	}
+	configuration.AddDefaultHeader("Token", token)
+	return configuration
+}
+
+// errorBody returns the response body the API client keeps in its errors.
+func errorBody(err error) []byte {
+	var swaggerErr qase.GenericSwaggerError
+	if errors.As(err, &swaggerErr) {
+		return swaggerErr.Body()
+	}
+	return nil
+}
+
+// isRunAlreadyCompleted tells whether the API refused to complete the run
+// because it is completed already.
+func isRunAlreadyCompleted(message string) bool {
+	return strings.Contains(strings.ToLower(message), "already completed")
+}
+
+// readBody reads the response body for the error message.
+// The response is nil when the request did not reach the API.
+func readBody(httpResp *http.Response) []byte {
+	if httpResp == nil || httpResp.Body == nil {
+		return nil
+	}
+	message, _ := io.ReadAll(httpResp.Body)
+	return message
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// MAX_RETRY_DELAY caps the wait before retrying a rate limited request.
+const MAX_RETRY_DELAY = 60 * time.Second
+
+// ApiStats counts the Qase API calls made while reporting.
+type ApiStats struct {
+	Calls   int
+	Retries int
+	// The rate limit headers of the last rate limited response
+	RateLimitRemaining string
+	RateLimitReset     string
+}
+
+var (
+	apiStats ApiStats
+	// apiStatsMu guards apiStats when results are submitted concurrently
+	apiStatsMu sync.Mutex
+)
+
+// withRetry calls the API and retries up to maxRetries times while the API
+// responds with 429 Too Many Requests, waiting as told by the rate limit headers.
+func withRetry(ctx context.Context, maxRetries int, call func() (*http.Response, error)) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		apiStatsMu.Lock()
+		apiStats.Calls++
+		apiStatsMu.Unlock()
+		httpResp, err := call()
+		if httpResp == nil || httpResp.StatusCode != http.StatusTooManyRequests || attempt >= maxRetries {
+			return httpResp, err
+		}
+
+		remaining := httpResp.Header.Get("X-RateLimit-Remaining")
+		reset := httpResp.Header.Get("X-RateLimit-Reset")
+		apiStatsMu.Lock()
+		apiStats.Retries++
+		apiStats.RateLimitRemaining = remaining
+		apiStats.RateLimitReset = reset
+		apiStatsMu.Unlock()
+		delay := retryDelay(httpResp.Header, attempt)
+		printVerbose("Rate limited by Qase API (X-RateLimit-Remaining: %v, X-RateLimit-Reset: %v), retrying in %v\n",
+			remaining, reset, delay)
+
+		if httpResp.Body != nil {
+			// The response is dropped for the retry, free its connection
+			httpResp.Body.Close()
+		}
+		if sleepContext(ctx, delay) != nil {
+			return httpResp, err
+		}
+	}
+}
+
+// sleepContext waits for the delay, or until the context is done in which case
+// it returns the error of the context. It is replaced in tests.
+var sleepContext = func(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// retryDelay waits until the rate limit resets. The reset header may be either
+// a Unix timestamp or a number of seconds. Without it, we back off exponentially.
+func retryDelay(header http.Header, attempt int) time.Duration {
+	delay := time.Duration(1<<attempt) * time.Second
+	if reset, err := strconv.ParseInt(header.Get("X-RateLimit-Reset"), 10, 64); err == nil && reset > 0 {
+		if reset > now().Unix() {
+			delay = time.Unix(reset, 0).Sub(now())
+		} else if reset < 1e9 {
+			delay = time.Duration(reset) * time.Second
+		}
+	}
+	if delay > MAX_RETRY_DELAY {
+		delay = MAX_RETRY_DELAY
+	}
+	if delay < 0 {
+		delay = 0
+	}
+	return delay
+}
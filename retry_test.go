@@ -0,0 +1,145 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newRateLimitedResponse(remaining string, reset string) *http.Response {
+	header := http.Header{}
+	header.Set("X-RateLimit-Remaining", remaining)
+	header.Set("X-RateLimit-Reset", reset)
+	return &http.Response{StatusCode: http.StatusTooManyRequests, Header: header}
+}
+
+func TestWithRetry(t *testing.T) {
+	originalSleepContext := sleepContext
+	defer func() {
+		sleepContext = originalSleepContext
+		apiStats = ApiStats{}
+	}()
+	delays := make([]time.Duration, 0)
+	sleepContext = func(ctx context.Context, d time.Duration) error {
+		delays = append(delays, d)
+		return ctx.Err()
+	}
+
+	t.Run("Retries on rate limit and records headers", func(t *testing.T) {
+		apiStats = ApiStats{}
+		delays = delays[:0]
+		responses := []*http.Response{
+			newRateLimitedResponse("0", "2"),
+			newRateLimitedResponse("0", "1"),
+			{StatusCode: http.StatusOK},
+		}
+		attempt := 0
+		httpResp, err := withRetry(context.Background(), 3, func() (*http.Response, error) {
+			httpResp := responses[attempt]
+			attempt++
+			if httpResp.StatusCode != http.StatusOK {
+				return httpResp, errors.New("429 Too Many Requests")
+			}
+			return httpResp, nil
+		})
+		require.Nil(t, err)
+		require.Equal(t, http.StatusOK, httpResp.StatusCode)
+		require.Equal(t, []time.Duration{2 * time.Second, time.Second}, delays)
+		require.Equal(t, ApiStats{Calls: 3, Retries: 2, RateLimitRemaining: "0", RateLimitReset: "1"}, apiStats)
+	})
+
+	t.Run("Gives up after max retries", func(t *testing.T) {
+		apiStats = ApiStats{}
+		delays = delays[:0]
+		httpResp, err := withRetry(context.Background(), 2, func() (*http.Response, error) {
+			return newRateLimitedResponse("0", "1"), errors.New("429 Too Many Requests")
+		})
+		require.NotNil(t, err)
+		require.Equal(t, http.StatusTooManyRequests, httpResp.StatusCode)
+		require.Equal(t, 3, apiStats.Calls)
+		require.Equal(t, 2, apiStats.Retries)
+	})
+
+	t.Run("Closes the body of the rate limited response", func(t *testing.T) {
+		apiStats = ApiStats{}
+		body := &closeRecorder{Reader: strings.NewReader("rate limited")}
+		attempt := 0
+		_, err := withRetry(context.Background(), 1, func() (*http.Response, error) {
+			attempt++
+			if attempt == 1 {
+				httpResp := newRateLimitedResponse("0", "1")
+				httpResp.Body = body
+				return httpResp, errors.New("429 Too Many Requests")
+			}
+			return &http.Response{StatusCode: http.StatusOK}, nil
+		})
+		require.Nil(t, err)
+		require.True(t, body.closed)
+	})
+
+	t.Run("Stops waiting when the context is done", func(t *testing.T) {
+		apiStats = ApiStats{}
+		sleepContext = originalSleepContext
+		defer func() {
+			sleepContext = func(ctx context.Context, d time.Duration) error {
+				delays = append(delays, d)
+				return ctx.Err()
+			}
+		}()
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		started := time.Now()
+		httpResp, err := withRetry(ctx, 3, func() (*http.Response, error) {
+			return newRateLimitedResponse("0", "30"), errors.New("429 Too Many Requests")
+		})
+		require.NotNil(t, err)
+		require.Equal(t, http.StatusTooManyRequests, httpResp.StatusCode)
+		require.Less(t, time.Since(started), 5*time.Second)
+		require.Equal(t, 1, apiStats.Calls)
+	})
+
+	t.Run("Does not retry other errors", func(t *testing.T) {
+		apiStats = ApiStats{}
+		_, err := withRetry(context.Background(), 3, func() (*http.Response, error) {
+			return &http.Response{StatusCode: http.StatusBadRequest}, errors.New("400 Bad Request")
+		})
+		require.NotNil(t, err)
+		require.Equal(t, ApiStats{Calls: 1}, apiStats)
+	})
+}
+
+type closeRecorder struct {
+	io.Reader
+	closed bool
+}
+
+func (c *closeRecorder) Close() error {
+	c.closed = true
+	return nil
+}
+
+func TestRetryDelay(t *testing.T) {
+	originalNow := now
+	defer func() { now = originalNow }()
+	fixedNow := time.Date(2024, 5, 27, 12, 0, 0, 0, time.UTC)
+	now = func() time.Time { return fixedNow }
+
+	header := http.Header{}
+	require.Equal(t, time.Second, retryDelay(header, 0))
+	require.Equal(t, 4*time.Second, retryDelay(header, 2))
+
+	header.Set("X-RateLimit-Reset", "5")
+	require.Equal(t, 5*time.Second, retryDelay(header, 0))
+
+	header.Set("X-RateLimit-Reset", "1716811210")
+	require.Equal(t, 10*time.Second, retryDelay(header, 0))
+
+	header.Set("X-RateLimit-Reset", "3600")
+	require.Equal(t, MAX_RETRY_DELAY, retryDelay(header, 0))
+}
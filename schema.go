@@ -0,0 +1,48 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+const (
+	// OUTPUT_SCHEMA_VERSION_1 is the original layout: the run and the test runs.
+	OUTPUT_SCHEMA_VERSION_1 = 1
+	// OUTPUT_SCHEMA_VERSION_2 adds the schema version and the per-status counts.
+	OUTPUT_SCHEMA_VERSION_2 = 2
+
+	OUTPUT_SCHEMA_VERSION_LATEST = OUTPUT_SCHEMA_VERSION_2
+)
+
+// reportOutputV1 is the layout of OUTPUT_SCHEMA_VERSION_1.
+type reportOutputV1 struct {
+	RunId    int32                 `json:"run_id"`
+	RunUrl   string                `json:"run_url"`
+	TestRuns []ReportOutputTestRun `json:"test_runs"`
+}
+
+func validateOutputSchemaVersion(version int) error {
+	if version < 0 || version > OUTPUT_SCHEMA_VERSION_LATEST {
+		return fmt.Errorf("unknown output schema version: %v, supported versions are 0 (latest) or 1 to %v", version, OUTPUT_SCHEMA_VERSION_LATEST)
+	}
+	return nil
+}
+
+// marshalOutput marshals the output in the layout of the schema version, so
+// consumers of an older layout keep working. Version 0 means the latest.
+func marshalOutput(output ReportOutput, version int) ([]byte, error) {
+	if err := validateOutputSchemaVersion(version); err != nil {
+		return nil, err
+	}
+	switch version {
+	case OUTPUT_SCHEMA_VERSION_1:
+		return json.Marshal(reportOutputV1{
+			RunId:    output.RunId,
+			RunUrl:   output.RunUrl,
+			TestRuns: output.TestRuns,
+		})
+	default:
+		output.SchemaVersion = OUTPUT_SCHEMA_VERSION_LATEST
+		return json.Marshal(output)
+	}
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarshalOutput(t *testing.T) {
+	output := ReportOutput{
+		RunId:  123,
+		RunUrl: "https://app.qase.io/run/DEMO/dashboard/123",
+		Counts: ReportOutputCounts{Passed: 1, Total: 1},
+		TestRuns: []ReportOutputTestRun{
+			{TestCaseId: 1, TestCaseUrl: "https://app.qase.io/case/DEMO-1", Status: TEST_CASE_RESULT_STATUS_PASSED},
+		},
+	}
+	testRuns := `"test_runs":[{"test_case_id":1,"test_case_url":"https://app.qase.io/case/DEMO-1","status":"passed"}]`
+
+	testcases := []struct {
+		name     string
+		version  int
+		expected string
+	}{
+		{
+			name:     "Version 1",
+			version:  OUTPUT_SCHEMA_VERSION_1,
+			expected: `{"run_id":123,"run_url":"https://app.qase.io/run/DEMO/dashboard/123",` + testRuns + `}`,
+		},
+		{
+			name:    "Version 2",
+			version: OUTPUT_SCHEMA_VERSION_2,
+			expected: `{"schema_version":2,"run_id":123,"run_url":"https://app.qase.io/run/DEMO/dashboard/123",` +
+				`"counts":{"passed":1,"failed":0,"skipped":0,"total":1},` + testRuns + `}`,
+		},
+		{
+			name:    "Default is the latest version",
+			version: 0,
+			expected: `{"schema_version":2,"run_id":123,"run_url":"https://app.qase.io/run/DEMO/dashboard/123",` +
+				`"counts":{"passed":1,"failed":0,"skipped":0,"total":1},` + testRuns + `}`,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := marshalOutput(output, tc.version)
+			require.Nil(t, err)
+			require.JSONEq(t, tc.expected, string(actual))
+		})
+	}
+
+	t.Run("Unknown version returns error", func(t *testing.T) {
+		_, err := marshalOutput(output, 3)
+		require.EqualError(t, err, "unknown output schema version: 3, supported versions are 0 (latest) or 1 to 2")
+	})
+}
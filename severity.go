@@ -0,0 +1,59 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// QASE_SEVERITIES are the severities of a case in Qase.
+var QASE_SEVERITIES = []string{"undefined", "blocker", "critical", "major", "normal", "minor", "trivial"}
+
+// severityMarker maps a marker in the test name, e.g. "@critical", to a severity.
+type severityMarker struct {
+	Marker   string
+	Severity string
+}
+
+func isQaseSeverity(severity string) bool {
+	for _, qaseSeverity := range QASE_SEVERITIES {
+		if severity == qaseSeverity {
+			return true
+		}
+	}
+	return false
+}
+
+// parseSeverityMap parses the `marker=severity` entries of --severity-map.
+func parseSeverityMap(entries []string) ([]severityMarker, error) {
+	markers := make([]severityMarker, 0, len(entries))
+	for _, entry := range entries {
+		marker, severity, found := strings.Cut(entry, "=")
+		marker = strings.TrimSpace(marker)
+		severity = strings.TrimSpace(severity)
+		if !found || marker == "" || severity == "" {
+			return nil, fmt.Errorf("invalid severity mapping %q, expected marker=severity", entry)
+		}
+		if !isQaseSeverity(severity) {
+			return nil, fmt.Errorf("invalid severity mapping %q, severity must be one of %v", entry, strings.Join(QASE_SEVERITIES, ", "))
+		}
+		markers = append(markers, severityMarker{Marker: marker, Severity: severity})
+	}
+	return markers, nil
+}
+
+// applySeverities sets the severity of the first marker found in the test name
+// of each result. The results without a marker keep the severity of their case.
+func applySeverities(results []ReportResult, markers []severityMarker) []ReportResult {
+	if len(markers) == 0 {
+		return results
+	}
+	for i := range results {
+		for _, marker := range markers {
+			if strings.Contains(results[i].Test, marker.Marker) {
+				results[i].Severity = marker.Severity
+				break
+			}
+		}
+	}
+	return results
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSeverityMap(t *testing.T) {
+	testcases := []struct {
+		name    string
+		entries []string
+		isError bool
+	}{
+		{name: "Valid", entries: []string{"@critical=critical", "@minor = minor"}},
+		{name: "Missing separator", entries: []string{"@critical"}, isError: true},
+		{name: "Empty marker", entries: []string{"=critical"}, isError: true},
+		{name: "Unknown severity", entries: []string{"@p0=urgent"}, isError: true},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := parseSeverityMap(tc.entries)
+			if tc.isError {
+				require.NotNil(t, err)
+			} else {
+				require.Nil(t, err)
+			}
+		})
+	}
+}
+
+func TestApplySeverities(t *testing.T) {
+	markers, err := parseSeverityMap([]string{"@critical=critical", "@minor=minor"})
+	require.Nil(t, err)
+
+	results := applySeverities([]ReportResult{
+		{Test: "TestCheckout_QASE-1/@critical", TestCaseId: 1},
+		{Test: "TestTooltip_QASE-2/@minor", TestCaseId: 2},
+		{Test: "TestLogin_QASE-3", TestCaseId: 3},
+	}, markers)
+
+	severities := make([]string, 0)
+	for _, result := range results {
+		severities = append(severities, result.Severity)
+	}
+	require.Equal(t, []string{"critical", "minor", ""}, severities)
+	require.Equal(t, "Test: TestCheckout_QASE-1/@critical\nSeverity: critical", createComment(results[0]))
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+// parseSince parses the --since threshold, either a duration before now, e.g.
+// "2h", or an RFC 3339 time.
+func parseSince(value string, now time.Time) (time.Time, error) {
+	if duration, err := time.ParseDuration(value); err == nil {
+		return now.Add(-duration), nil
+	}
+	since, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("since must be a duration, e.g. 2h, or in RFC 3339 format, got %v", value)
+	}
+	return since, nil
+}
+
+// filterSince drops the results older than since, e.g. stale lines of a cached
+// run. The results without a time are kept, and all are kept if since is zero.
+func filterSince(results []ReportResult, since time.Time) []ReportResult {
+	if since.IsZero() {
+		return results
+	}
+	filtered := make([]ReportResult, 0, len(results))
+	for _, result := range results {
+		if !result.Time.IsZero() && result.Time.Before(since) {
+			printVerbose("Dropping result of %v from %v, older than --since\n", result.Test, result.Time.Format(time.RFC3339))
+			continue
+		}
+		filtered = append(filtered, result)
+	}
+	return filtered
+}
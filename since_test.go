@@ -0,0 +1,80 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSince(t *testing.T) {
+	now := time.Date(2024, 5, 27, 12, 0, 0, 0, time.UTC)
+	testcases := []struct {
+		value    string
+		expected time.Time
+		err      bool
+	}{
+		{value: "2h", expected: time.Date(2024, 5, 27, 10, 0, 0, 0, time.UTC)},
+		{value: "30m", expected: time.Date(2024, 5, 27, 11, 30, 0, 0, time.UTC)},
+		{value: "2024-05-26T08:00:00Z", expected: time.Date(2024, 5, 26, 8, 0, 0, 0, time.UTC)},
+		{value: "yesterday", err: true},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.value, func(t *testing.T) {
+			since, err := parseSince(tc.value, now)
+			if tc.err {
+				require.NotNil(t, err)
+				return
+			}
+			require.Nil(t, err)
+			require.True(t, tc.expected.Equal(since))
+		})
+	}
+}
+
+func TestFilterSince(t *testing.T) {
+	since := time.Date(2024, 5, 27, 12, 0, 0, 0, time.UTC)
+	results := []ReportResult{
+		{TestCaseId: 1, Time: since.Add(-time.Hour)},
+		{TestCaseId: 2, Time: since},
+		{TestCaseId: 3, Time: since.Add(time.Minute)},
+		{TestCaseId: 4},
+	}
+
+	caseIds := func(results []ReportResult) []int64 {
+		ids := make([]int64, 0, len(results))
+		for _, result := range results {
+			ids = append(ids, result.TestCaseId)
+		}
+		return ids
+	}
+	require.Equal(t, []int64{2, 3, 4}, caseIds(filterSince(results, since)))
+	require.Equal(t, []int64{1, 2, 3, 4}, caseIds(filterSince(results, time.Time{})))
+}
+
+func TestProcessFileSince(t *testing.T) {
+	originalConfig, originalSinceTime := config, sinceTime
+	defer func() { config, sinceTime = originalConfig, originalSinceTime }()
+
+	input := strings.Join([]string{
+		`{"Time":"2020-01-01T00:00:00Z","Action":"pass","Package":"example.com/foo","Test":"TestStale_QASE-1","Elapsed":0.1}`,
+		`{"Time":"` + time.Now().UTC().Format(time.RFC3339) + `","Action":"fail","Package":"example.com/foo","Test":"TestFresh_QASE-2","Elapsed":0.1}`,
+		`{"Action":"pass","Package":"example.com/foo","Test":"TestUntimed_QASE-3","Elapsed":0.1}`,
+	}, "\n")
+	path := filepath.Join(t.TempDir(), "results.jsonl")
+	require.Nil(t, os.WriteFile(path, []byte(input), 0o644))
+
+	config.Since = "24h"
+	require.Nil(t, compilePatterns(config))
+	results, err := processFile(path)
+	require.Nil(t, err)
+	require.Len(t, results, 2)
+	require.Equal(t, int64(2), results[0].TestCaseId)
+	require.Equal(t, int64(3), results[1].TestCaseId)
+
+	config.Since = "last week"
+	require.NotNil(t, compilePatterns(config))
+}
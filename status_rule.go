@@ -0,0 +1,66 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/petrabarus/go-qase-testing-reporter/pkg/parser"
+)
+
+// StatusRuleData is the data available to the --status-rule template.
+type StatusRuleData struct {
+	Package string
+	Test    string
+	Status  string
+	Output  string
+}
+
+var statusRuleFuncs = template.FuncMap{
+	"contains":  strings.Contains,
+	"hasPrefix": strings.HasPrefix,
+	"hasSuffix": strings.HasSuffix,
+	"matches": func(pattern string, s string) (bool, error) {
+		return regexp.MatchString(pattern, s)
+	},
+}
+
+// parseStatusRule parses the --status-rule template, nil when unset.
+func parseStatusRule(rule string) (*template.Template, error) {
+	if rule == "" {
+		return nil, nil
+	}
+	return template.New("status-rule").Funcs(statusRuleFuncs).Parse(rule)
+}
+
+// applyStatusRule derives the status of each result with the rule, e.g.
+// `{{if contains .Output "connection reset"}}skipped{{end}}`. A result keeps
+// its status when the rule renders empty.
+func applyStatusRule(results []ReportResult, rule *template.Template) ([]ReportResult, error) {
+	if rule == nil {
+		return results, nil
+	}
+	for i, result := range results {
+		var buf bytes.Buffer
+		err := rule.Execute(&buf, StatusRuleData{
+			Package: result.Package,
+			Test:    result.Test,
+			Status:  result.Status,
+			Output:  result.Output,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to evaluate status rule for %v: %v", result.Test, err)
+		}
+		status := strings.TrimSpace(buf.String())
+		if status == "" {
+			continue
+		}
+		if !parser.IsQaseStatus(status) {
+			return nil, fmt.Errorf("status rule returned unknown status %q for %v", status, result.Test)
+		}
+		results[i].Status = status
+	}
+	return results, nil
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyStatusRule(t *testing.T) {
+	newResults := func() []ReportResult {
+		return []ReportResult{
+			{Test: "TestFlaky_QASE-1", TestCaseId: 1, Status: TEST_CASE_RESULT_STATUS_FAILED, Output: "dial tcp: connection reset by peer\n"},
+			{Test: "TestBroken_QASE-2", TestCaseId: 2, Status: TEST_CASE_RESULT_STATUS_FAILED, Output: "expected 1, got 2\n"},
+			{Test: "TestOk_QASE-3", TestCaseId: 3, Status: TEST_CASE_RESULT_STATUS_PASSED},
+		}
+	}
+
+	testcases := []struct {
+		name     string
+		rule     string
+		expected []string
+		isError  bool
+	}{
+		{
+			name:     "No rule",
+			rule:     "",
+			expected: []string{"failed", "failed", "passed"},
+		},
+		{
+			name:     "Rule matching the output",
+			rule:     `{{if contains .Output "connection reset"}}skipped{{end}}`,
+			expected: []string{"skipped", "failed", "passed"},
+		},
+		{
+			name:     "Rule matching the test name",
+			rule:     `{{if matches "^TestBroken" .Test}}passed{{end}}`,
+			expected: []string{"failed", "passed", "passed"},
+		},
+		{
+			name:     "Rule matching nothing",
+			rule:     `{{if contains .Output "timeout"}}skipped{{end}}`,
+			expected: []string{"failed", "failed", "passed"},
+		},
+		{
+			name:    "Rule returning an unknown status",
+			rule:    `flaky`,
+			isError: true,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			rule, err := parseStatusRule(tc.rule)
+			require.Nil(t, err)
+			results, err := applyStatusRule(newResults(), rule)
+			if tc.isError {
+				require.NotNil(t, err)
+				return
+			}
+			require.Nil(t, err)
+			statuses := make([]string, 0)
+			for _, result := range results {
+				statuses = append(statuses, result.Status)
+			}
+			require.Equal(t, tc.expected, statuses)
+		})
+	}
+
+	t.Run("Invalid rule", func(t *testing.T) {
+		_, err := parseStatusRule(`{{if}}`)
+		require.NotNil(t, err)
+	})
+}
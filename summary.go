@@ -0,0 +1,51 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// stderr receives the warnings and the summary, replaced in tests.
+var stderr io.Writer = os.Stderr
+
+// printHumanSummary prints the counts by status, the failed case IDs and the
+// slowest results, e.g. "12 passed, 2 failed, 1 skipped", for a quick look
+// without parsing the JSON.
+func printHumanSummary(w io.Writer, output ReportOutput, slowest []ReportResult) {
+	fmt.Fprintf(w, "%d passed, %d failed, %d skipped\n", output.Counts.Passed, output.Counts.Failed, output.Counts.Skipped)
+	failed := make([]string, 0, output.Counts.Failed)
+	for _, testRun := range output.TestRuns {
+		if testRun.Status == TEST_CASE_RESULT_STATUS_FAILED {
+			failed = append(failed, fmt.Sprint(testRun.TestCaseId))
+		}
+	}
+	if len(failed) > 0 {
+		fmt.Fprintf(w, "Failed cases: %v\n", strings.Join(failed, ", "))
+	}
+	if len(slowest) > 0 {
+		fmt.Fprintln(w, "Slowest cases:")
+		for _, result := range slowest {
+			fmt.Fprintf(w, "  %d %v: %v\n", result.TestCaseId, result.Test, time.Duration(result.TimeMs)*time.Millisecond)
+		}
+	}
+}
+
+// slowestResults returns the n results with the longest TimeMs, slowest first.
+func slowestResults(results []ReportResult, n int) []ReportResult {
+	if n <= 0 {
+		return nil
+	}
+	sorted := make([]ReportResult, len(results))
+	copy(sorted, results)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].TimeMs > sorted[j].TimeMs
+	})
+	if len(sorted) > n {
+		sorted = sorted[:n]
+	}
+	return sorted
+}
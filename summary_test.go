@@ -0,0 +1,78 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrintHumanSummary(t *testing.T) {
+	testcases := []struct {
+		name     string
+		output   ReportOutput
+		expected string
+	}{
+		{
+			name: "With failures",
+			output: createOutput(1, []ReportResultOutput{
+				{TestCaseId: 1, Status: TEST_CASE_RESULT_STATUS_PASSED},
+				{TestCaseId: 2, Status: TEST_CASE_RESULT_STATUS_FAILED},
+				{TestCaseId: 3, Status: TEST_CASE_RESULT_STATUS_SKIPPED},
+				{TestCaseId: 4, Status: TEST_CASE_RESULT_STATUS_FAILED},
+			}),
+			expected: "1 passed, 2 failed, 1 skipped\nFailed cases: 2, 4\n",
+		},
+		{
+			name: "Without failures",
+			output: createOutput(1, []ReportResultOutput{
+				{TestCaseId: 1, Status: TEST_CASE_RESULT_STATUS_PASSED},
+			}),
+			expected: "1 passed, 0 failed, 0 skipped\n",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			printHumanSummary(&buf, tc.output, nil)
+			require.Equal(t, tc.expected, buf.String())
+		})
+	}
+}
+
+func TestSlowestResults(t *testing.T) {
+	results := []ReportResult{
+		{TestCaseId: 1, TimeMs: 120},
+		{TestCaseId: 2, Test: "TestImport_QASE-2", TimeMs: 3400},
+		{TestCaseId: 3, TimeMs: 15},
+		{TestCaseId: 4, TimeMs: 980},
+		{TestCaseId: 5, TimeMs: 3400},
+	}
+
+	testcases := []struct {
+		name     string
+		n        int
+		expected []int64
+	}{
+		{name: "Disabled", n: 0, expected: []int64{}},
+		{name: "Top 3", n: 3, expected: []int64{2, 5, 4}},
+		{name: "More than the results", n: 10, expected: []int64{2, 5, 4, 1, 3}},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			ids := make([]int64, 0)
+			for _, result := range slowestResults(results, tc.n) {
+				ids = append(ids, result.TestCaseId)
+			}
+			require.Equal(t, tc.expected, ids)
+		})
+	}
+
+	t.Run("Printed in the summary", func(t *testing.T) {
+		var buf bytes.Buffer
+		printHumanSummary(&buf, ReportOutput{}, slowestResults(results, 1))
+		require.Equal(t, "0 passed, 0 failed, 0 skipped\nSlowest cases:\n  2 TestImport_QASE-2: 3.4s\n", buf.String())
+	})
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"regexp"
+	"runtime/debug"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// MAX_SLUG_LENGTH caps the length of a slug unless another is given, as Qase
+// caps the run title at 255 characters.
+const MAX_SLUG_LENGTH = 255
+
+// slugUnsafeRegexp matches the runs of characters replaced in a slug.
+var slugUnsafeRegexp = regexp.MustCompile(`[^a-z0-9._-]+`)
+
+// titleFuncs are the functions available in the run title and description
+// templates, e.g. `{{slug (env "CI_JOB_NAME")}} {{env "CI_JOB_ID"}}`.
+var titleFuncs = template.FuncMap{
+	"env":  os.Getenv,
+	"slug": slug,
+}
+
+// RunTitleData is the data available when rendering the run title template,
+// e.g. `CI run {{.Date}} {{.Commit}}`.
+type RunTitleData struct {
+	Date   string
+	Time   string
+	Commit string
+	Branch string
+	// Title is the rendered run title, for the description template, e.g.
+	// `{{slug .Title}}`. It is empty while rendering the title itself.
+	Title string
+}
+
+// now is replaced in tests to render titles against a fixed clock.
+var now = time.Now
+
+func newRunTitleData(t time.Time) RunTitleData {
+	return RunTitleData{
+		Date:   t.Format("2006-01-02"),
+		Time:   t.Format("15:04:05"),
+		Commit: getCommit(),
+		Branch: getBranch(),
+	}
+}
+
+// renderRunTitle renders the title as a text/template against the data.
+// Titles without template actions are returned verbatim.
+func renderRunTitle(title string, data RunTitleData) (string, error) {
+	return renderTemplate("run-title", title, data)
+}
+
+// renderRunDescription renders the description the same way as the title.
+func renderRunDescription(description string, data RunTitleData) (string, error) {
+	return renderTemplate("run-description", description, data)
+}
+
+func renderTemplate(name string, text string, data RunTitleData) (string, error) {
+	if !strings.Contains(text, "{{") {
+		return text, nil
+	}
+	tmpl, err := template.New(name).Funcs(titleFuncs).Parse(text)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// slug turns the text into lowercase letters, digits, dots, underscores, and
+// hyphens, replacing anything else, e.g. the slashes of a branch name, with a
+// hyphen. It is cut to maxLength, if given, or MAX_SLUG_LENGTH characters.
+func slug(text string, maxLength ...int) string {
+	limit := MAX_SLUG_LENGTH
+	if len(maxLength) > 0 && maxLength[0] > 0 {
+		limit = maxLength[0]
+	}
+	slugged := slugUnsafeRegexp.ReplaceAllString(strings.ToLower(text), "-")
+	slugged = strings.Trim(slugged, "-")
+	if len(slugged) > limit {
+		slugged = strings.TrimRight(slugged[:limit], "-")
+	}
+	return slugged
+}
+
+// getCommit returns the VCS revision embedded by the Go toolchain, falling back
+// to the commit set through ldflags on release builds.
+func getCommit() string {
+	if buildInfo, ok := debug.ReadBuildInfo(); ok {
+		for _, setting := range buildInfo.Settings {
+			if setting.Key == "vcs.revision" {
+				return setting.Value
+			}
+		}
+	}
+	if Commit != "none" {
+		return Commit
+	}
+	return ""
+}
+
+// The build info does not record the branch, so we look at the variables
+// set by the common CI providers.
+func getBranch() string {
+	for _, key := range []string{"GITHUB_HEAD_REF", "GITHUB_REF_NAME", "CI_COMMIT_REF_NAME", "BRANCH_NAME"} {
+		if branch := os.Getenv(key); branch != "" {
+			return branch
+		}
+	}
+	return ""
+}
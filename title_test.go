@@ -0,0 +1,108 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderRunTitle(t *testing.T) {
+	fixedNow := time.Date(2024, 5, 27, 19, 33, 56, 0, time.UTC)
+	data := newRunTitleData(fixedNow)
+	data.Commit = "1a2b3c4d"
+	data.Branch = "main"
+
+	testcases := []struct {
+		name     string
+		title    string
+		expected string
+	}{
+		{
+			name:     "Plain title is used verbatim",
+			title:    "Pipeline run 20240527",
+			expected: "Pipeline run 20240527",
+		},
+		{
+			name:     "Templated title with date and commit",
+			title:    "CI run {{.Date}} {{.Commit}}",
+			expected: "CI run 2024-05-27 1a2b3c4d",
+		},
+		{
+			name:     "Templated title with time and branch",
+			title:    "{{.Branch}} at {{.Time}}",
+			expected: "main at 19:33:56",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := renderRunTitle(tc.title, data)
+			require.Nil(t, err)
+			require.Equal(t, tc.expected, actual)
+		})
+	}
+
+	t.Run("Invalid template returns error", func(t *testing.T) {
+		_, err := renderRunTitle("CI run {{.Date", data)
+		require.NotNil(t, err)
+	})
+}
+
+func TestSlug(t *testing.T) {
+	testcases := []struct {
+		text      string
+		maxLength []int
+		expected  string
+	}{
+		{text: "feature/login-page", expected: "feature-login-page"},
+		{text: "  Nightly Build #42  ", expected: "nightly-build-42"},
+		{text: "release/v1.2.3", expected: "release-v1.2.3"},
+		{text: "snake_case", expected: "snake_case"},
+		{text: "feature/very-long-branch-name", maxLength: []int{12}, expected: "feature-very"},
+		{text: strings.Repeat("a", 300), expected: strings.Repeat("a", MAX_SLUG_LENGTH)},
+		{text: "///", expected: ""},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.text, func(t *testing.T) {
+			require.Equal(t, tc.expected, slug(tc.text, tc.maxLength...))
+		})
+	}
+}
+
+func TestRenderRunTitleFuncs(t *testing.T) {
+	t.Setenv("CI_JOB_ID", "1234")
+	t.Setenv("CI_JOB_NAME", "Test / Integration")
+	data := RunTitleData{Date: "2024-05-27", Branch: "feature/login"}
+
+	testcases := []struct {
+		name     string
+		title    string
+		expected string
+	}{
+		{name: "env", title: `Job {{env "CI_JOB_ID"}}`, expected: "Job 1234"},
+		{name: "Unset env", title: `Job {{env "CI_MISSING"}}`, expected: "Job "},
+		{name: "slug of env", title: `{{slug (env "CI_JOB_NAME")}}-{{env "CI_JOB_ID"}}`, expected: "test-integration-1234"},
+		{name: "slug of field", title: `{{slug .Branch}} {{.Date}}`, expected: "feature-login 2024-05-27"},
+		{name: "slug with length", title: `{{slug .Branch 7}}`, expected: "feature"},
+		{name: "slug of the composed title", title: `{{printf "%s/%s" (env "CI_JOB_NAME") .Branch | slug}}`, expected: "test-integration-feature-login"},
+		{name: "slug of the title in the title", title: `{{slug .Title}}`, expected: ""},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := renderRunTitle(tc.title, data)
+			require.Nil(t, err)
+			require.Equal(t, tc.expected, actual)
+		})
+	}
+
+	description, err := renderRunDescription(`Job {{env "CI_JOB_ID"}}`, data)
+	require.Nil(t, err)
+	require.Equal(t, "Job 1234", description)
+
+	data.Title = "Test / Integration 1234"
+	description, err = renderRunDescription(`Run {{slug .Title}}`, data)
+	require.Nil(t, err)
+	require.Equal(t, "Run test-integration-1234", description)
+}
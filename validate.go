@@ -0,0 +1,86 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/petrabarus/go-qase-testing-reporter/pkg/parser"
+)
+
+// lineIssue is an invalid line of the input, see --validate-only.
+type lineIssue struct {
+	Line int
+	Err  error
+}
+
+// validateFiles checks every line of the files without reporting them, prints
+// the invalid lines and a summary to w, and returns the number of invalid lines.
+func validateFiles(w io.Writer, filenames []string, format string) (invalid int, err error) {
+	valid := 0
+	for _, filename := range filenames {
+		fileValid, issues, err := validateFile(filename, format)
+		if err != nil {
+			return invalid, fmt.Errorf("failed to validate %v: %v", filename, err)
+		}
+		for _, issue := range issues {
+			fmt.Fprintf(w, "%v:%d: %v\n", filename, issue.Line, issue.Err)
+		}
+		valid += fileValid
+		invalid += len(issues)
+	}
+	fmt.Fprintf(w, "%d valid lines, %d invalid lines\n", valid, invalid)
+	return invalid, nil
+}
+
+func validateFile(filename string, format string) (valid int, issues []lineIssue, err error) {
+	var reader io.Reader = os.Stdin
+	if filename != "-" {
+		file, err := os.Open(filename)
+		if err != nil {
+			return 0, nil, err
+		}
+		defer file.Close()
+		reader = file
+	}
+	reader, err = decompressReader(reader)
+	if err != nil {
+		return
+	}
+	return validateReader(reader, format)
+}
+
+// validateReader checks each line of the input. Blank lines are ignored.
+func validateReader(reader io.Reader, format string) (valid int, issues []lineIssue, err error) {
+	issues = make([]lineIssue, 0)
+	// One parser for the whole input, which keeps the ID directives of the tests
+	p := newParser()
+	scanner := parser.NewLineScanner(reader, config.MaxLineBytes)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		if err := validateLine(p, line, format); err != nil {
+			issues = append(issues, lineIssue{Line: lineNumber, Err: err})
+			continue
+		}
+		valid++
+	}
+	err = scanner.Err()
+	return
+}
+
+// validateLine checks that the line parses and, for a test result, that it
+// has a Qase ID.
+func validateLine(p *parser.Parser, line string, format string) error {
+	if format == INPUT_FORMAT_SIMPLE {
+		if strings.HasPrefix(strings.TrimSpace(line), "#") {
+			return nil
+		}
+		_, err := processSimpleLine(strings.TrimSpace(line))
+		return err
+	}
+	return p.ValidateLine(line)
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateFiles(t *testing.T) {
+	input := strings.Join([]string{
+		`{"Action":"run","Package":"example.com/foo","Test":"TestFoo_QASE-1"}`,
+		`{"Action":"pass","Package":"example.com/foo","Test":"TestFoo_QASE-1","Elapsed":0.1}`,
+		`{"Action":"pass","Package":"example.com/foo","Test":"TestFoo","Elapsed":0.1}`,
+		``,
+		`not json`,
+		`{"Action":"pass","Package":"example.com/foo","Elapsed":0.1}`,
+		`{"Action":"fail","Package":"example.com/foo","Test":"TestBar_QASE-2"`,
+	}, "\n")
+	filename := filepath.Join(t.TempDir(), "report.jsonl")
+	require.Nil(t, os.WriteFile(filename, []byte(input), 0644))
+
+	var buf bytes.Buffer
+	invalid, err := validateFiles(&buf, []string{filename}, INPUT_FORMAT_TEST2JSON)
+	require.Nil(t, err)
+	require.Equal(t, 3, invalid)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 4)
+	require.True(t, strings.HasPrefix(lines[0], filename+":3: no Qase ID found in test TestFoo"), lines[0])
+	require.True(t, strings.HasPrefix(lines[1], filename+":5: invalid JSON"), lines[1])
+	require.True(t, strings.HasPrefix(lines[2], filename+":7: invalid JSON"), lines[2])
+	require.Equal(t, "3 valid lines, 3 invalid lines", lines[3])
+}
+
+func TestValidateReaderSimpleFormat(t *testing.T) {
+	input := "# results\n123 passed 250\n124 broken\nQASE-125 skipped\n"
+	valid, issues, err := validateReader(strings.NewReader(input), INPUT_FORMAT_SIMPLE)
+	require.Nil(t, err)
+	require.Equal(t, 3, valid)
+	require.Len(t, issues, 1)
+	require.Equal(t, 3, issues[0].Line)
+}
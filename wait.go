@@ -0,0 +1,60 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	qase "go.qase.io/client"
+)
+
+// WAIT_FOR_RESULTS_INTERVAL is the time between polls of the run, see --wait-for-results.
+const WAIT_FOR_RESULTS_INTERVAL = 2 * time.Second
+
+// registeredResults counts the cases of the run with a result.
+func registeredResults(run qase.Run) int {
+	if run.Stats == nil {
+		return 0
+	}
+	stats := run.Stats
+	return int(stats.Passed + stats.Failed + stats.Blocked + stats.Skipped + stats.Retest + stats.Invalid)
+}
+
+// submittedCases counts the cases with a submitted result, which is what the
+// run stats count.
+func submittedCases(results []ReportResult) int {
+	cases := make(map[int64]bool)
+	for _, result := range results {
+		cases[result.TestCaseId] = true
+	}
+	return len(cases)
+}
+
+// waitForResults polls the run until Qase registered the results of all
+// submitted cases, since it processes bulk results asynchronously and a run
+// completed too early misses them. It gives up with a warning after timeout,
+// and does nothing without one.
+func waitForResults(reporter QaseReporter, id int32, expected int, timeout time.Duration) error {
+	if timeout <= 0 {
+		return nil
+	}
+	deadline := now().Add(timeout)
+	for {
+		run, err := reporter.GetRun(ctx, config.QaseProject, id)
+		if err != nil {
+			return err
+		}
+		registered := registeredResults(run)
+		if registered >= expected {
+			printVerbose("Qase registered %d of %d results\n", registered, expected)
+			return nil
+		}
+		if !now().Before(deadline) {
+			fmt.Fprintf(stderr, "Warning: Qase registered %d of %d results after %v, completing the run anyway\n", registered, expected, timeout)
+			return nil
+		}
+		printVerbose("Waiting for Qase to register the results, %d of %d so far\n", registered, expected)
+		if err := sleepContext(ctx, WAIT_FOR_RESULTS_INTERVAL); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	qase "go.qase.io/client"
+)
+
+func TestWaitForResults(t *testing.T) {
+	originalSleepContext, originalNow, originalStderr := sleepContext, now, stderr
+	defer func() { sleepContext, now, stderr = originalSleepContext, originalNow, originalStderr }()
+	current := time.Date(2024, 5, 27, 12, 0, 0, 0, time.UTC)
+	now = func() time.Time { return current }
+	sleeps := 0
+	sleepContext = func(ctx context.Context, d time.Duration) error {
+		sleeps++
+		current = current.Add(d)
+		return nil
+	}
+	partial := qase.Run{Stats: &qase.RunStats{Total: 3, Untested: 2, Passed: 1}}
+	full := qase.Run{Stats: &qase.RunStats{Total: 3, Passed: 2, Failed: 1}}
+
+	t.Run("Waits for the full count", func(t *testing.T) {
+		sleeps = 0
+		reporter := &fakeReporter{getRuns: []qase.Run{{}, partial, full}}
+		require.Nil(t, waitForResults(reporter, 10, 3, time.Minute))
+		require.Equal(t, []string{"GetRun", "GetRun", "GetRun"}, reporter.calls)
+		require.Equal(t, 2, sleeps)
+	})
+
+	t.Run("Gives up after the timeout", func(t *testing.T) {
+		var buf strings.Builder
+		stderr = &buf
+		reporter := &fakeReporter{getRuns: []qase.Run{partial}}
+		require.Nil(t, waitForResults(reporter, 10, 3, 5*time.Second))
+		require.Len(t, reporter.calls, 4)
+		require.Contains(t, buf.String(), "Warning: Qase registered 1 of 3 results after 5s")
+	})
+
+	t.Run("Does not wait without a timeout", func(t *testing.T) {
+		reporter := &fakeReporter{getRuns: []qase.Run{partial}}
+		require.Nil(t, waitForResults(reporter, 10, 3, 0))
+		require.Empty(t, reporter.calls)
+	})
+}
+
+func TestWaitForResultsCancelled(t *testing.T) {
+	originalCtx := ctx
+	defer func() { ctx = originalCtx }()
+	var cancel context.CancelFunc
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+
+	reporter := &fakeReporter{getRuns: []qase.Run{{Stats: &qase.RunStats{Total: 3, Untested: 2, Passed: 1}}}}
+	err := waitForResults(reporter, 10, 3, time.Hour)
+	require.True(t, errors.Is(err, context.Canceled))
+	require.Equal(t, []string{"GetRun"}, reporter.calls)
+}
+
+func TestRunReportWaitForResults(t *testing.T) {
+	originalConfig, originalSleepContext := config, sleepContext
+	defer func() { config, sleepContext = originalConfig, originalSleepContext }()
+	sleepContext = func(context.Context, time.Duration) error { return nil }
+	config.QaseProject = "DEMO"
+	config.WaitForResults = time.Minute
+
+	reporter := &fakeReporter{runId: 10, getRuns: []qase.Run{
+		{Stats: &qase.RunStats{Total: 2, Untested: 1, Passed: 1}},
+		{Stats: &qase.RunStats{Total: 2, Passed: 1, Failed: 1}},
+	}}
+	_, err := runReport(reporter, nil, []ReportResult{
+		{TestCaseId: 1, Status: TEST_CASE_RESULT_STATUS_PASSED},
+		{TestCaseId: 2, Status: TEST_CASE_RESULT_STATUS_FAILED},
+		{TestCaseId: 2, Status: TEST_CASE_RESULT_STATUS_FAILED},
+	})
+	require.Nil(t, err)
+	require.Equal(t, []string{"CreateRun", "CreateResultBulk", "GetRun", "GetRun", "CompleteRun"}, reporter.calls)
+}